@@ -9,6 +9,9 @@ import (
 var exitHandlers = []func(){}
 var mux = &sync.Mutex{}
 
+// osExit terminates the program, it is replaced in tests.
+var osExit = os.Exit
+
 // runHandler tries to run exactly one handler and if not successful, writes error message to stderr
 func runHandler(handler func()) {
 	defer func() {
@@ -33,7 +36,7 @@ func Exit(code int) {
 	mux.Lock()
 	defer mux.Unlock()
 	runHandlers()
-	os.Exit(code)
+	osExit(code)
 }
 
 // Exit runs all the exitHandlers and then terminates the program using
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flushRecorder records entries and flushes.
+type flushRecorder []string
+
+func (r *flushRecorder) HandleLog(e *Entry) error {
+	*r = append(*r, e.LevelName)
+	return nil
+}
+
+func (r *flushRecorder) Flush(ctx context.Context) error {
+	*r = append(*r, "flush")
+	return nil
+}
+
+func TestLogger_ExitOnFatal(t *testing.T) {
+	var handled flushRecorder
+	var code = -1
+
+	osExit = func(c int) {
+		code = c
+	}
+	defer func() { osExit = os.Exit }()
+
+	l := &Logger{
+		Handler:     &handled,
+		Level:       InfoLevel,
+		ExitOnFatal: true,
+	}
+
+	l.AddExitHandler(func() {
+		handled = append(handled, "exit")
+	})
+
+	l.Error("boom")
+	assert.Equal(t, -1, code)
+
+	l.Emergency("boom")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, flushRecorder{"error", "emergency", "flush", "exit"}, handled)
+}
+
+func TestLogger_ExitOnFatal_filtered(t *testing.T) {
+	var handled flushRecorder
+	var code = -1
+
+	osExit = func(c int) {
+		code = c
+	}
+	defer func() { osExit = os.Exit }()
+
+	l := &Logger{
+		Handler:     &handled,
+		Level:       InfoLevel,
+		ExitOnFatal: true,
+	}
+	l.SetNamedLevel("db", EmergencyLevel)
+
+	l.Named("db").Fatal("boom")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, flushRecorder{"flush"}, handled)
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller describes the origin of a log entry.
+type Caller struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// String returns the short form of the caller, e.g. "file.go:42".
+func (c Caller) String() string {
+	return path.Base(c.File) + ":" + strconv.Itoa(c.Line)
+}
+
+// pkgPrefix is the prefix of all function names in this package.
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	return name[:slash+strings.Index(name[slash:], ".")+1]
+}()
+
+// skipCallers are prefixes of functions which are never reported as callers,
+// i.e. this package and the stdlib log which may write into Writer.
+var skipCallers = []string{pkgPrefix, "log."}
+
+// findCaller returns the first frame outside of this package, so the
+// result is the same no matter whether the package level functions, Logger
+// methods or Entry methods were used.
+func findCaller() *Caller {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !skipped(frame.Function) {
+			return &Caller{
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: frame.Function,
+			}
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// skipped reports whether `function` belongs to one of skipCallers.
+func skipped(function string) bool {
+	for _, prefix := range skipCallers {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package log
+
+import "context"
+
+// contextKey is the private type of the key the entry is stored under.
+type contextKey struct{}
+
+// NewContext returns a copy of `ctx` carrying entry `e`. The entry keeps its
+// fields, env, project and hostname, so it can travel through the call stack
+// instead of being passed around explicitly.
+func NewContext(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the entry stored in `ctx` by NewContext with `ctx`
+// attached to it. When there is none, a new entry of the default logger is
+// returned instead.
+func FromContext(ctx context.Context) *Entry {
+	if e, ok := ctx.Value(contextKey{}).(*Entry); ok && e != nil {
+		return e.WithContext(ctx)
+	}
+
+	return Log.WithContext(ctx)
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+)
+
+type ctxKey string
+
+func TestFromContext(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	e := l.WithField("request", "abc").SetEnvProject("prod", "golog").WithField("user", "tobi")
+	ctx := context.WithValue(context.Background(), ctxKey("id"), "123")
+	ctx = log.NewContext(ctx, e)
+
+	log.FromContext(ctx).WithField("file", "sloth.png").Info("upload")
+
+	assert.Len(t, h.Entries, 1)
+
+	entry := h.Entries[0]
+	assert.Equal(t, "upload", entry.Message)
+	assert.Equal(t, "prod", entry.Env)
+	assert.Equal(t, "golog", entry.Project)
+	assert.Equal(t, log.Fields{"user": "tobi", "file": "sloth.png"}, entry.Fields)
+	assert.Equal(t, "123", entry.Context.Value(ctxKey("id")))
+}
+
+func TestFromContext_default(t *testing.T) {
+	h := memory.New()
+	log.SetHandler(h)
+
+	ctx := context.WithValue(context.Background(), ctxKey("id"), "123")
+	log.FromContext(ctx).Info("hello")
+
+	assert.Len(t, h.Entries, 1)
+	assert.Equal(t, "123", h.Entries[0].Context.Value(ctxKey("id")))
+}
@@ -3,7 +3,6 @@ package log
 import (
 	"bytes"
 	"fmt"
-	"log"
 	"sort"
 )
 
@@ -22,7 +21,7 @@ func (a byName) Less(i, j int) bool { return a[i].Name < a[j].Name }
 
 // handleStdLog outpouts to the stlib log.
 func handleStdLog(e *Entry) error {
-	level := levelNames[e.Level]
+	level := Level(e.Level).String()
 
 	var fields []field
 
@@ -39,7 +38,7 @@ func handleStdLog(e *Entry) error {
 		fmt.Fprintf(&b, " %s=%v", f.Name, f.Value)
 	}
 
-	log.Println(b.String())
+	stdPrintln(e, b.String())
 
 	return nil
 }
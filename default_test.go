@@ -0,0 +1,64 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleStdLog_redirected(t *testing.T) {
+	l := &Logger{
+		Handler: HandlerFunc(handleStdLog),
+		Level:   InfoLevel,
+	}
+
+	stdlog.SetOutput(l.Writer(WarnLevel))
+	stdlog.SetFlags(0)
+	defer stdlog.SetOutput(os.Stderr)
+	defer stdlog.SetFlags(stdlog.LstdFlags)
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	stdlog.Print("hello")
+	w.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	assert.Equal(t, " warn hello                    \n", buf.String())
+}
+
+func TestHandleStdLog_redirectedDefault(t *testing.T) {
+	l := Log.(*Logger)
+	handler := l.GetHandler()
+	l.SetHandler(HandlerFunc(handleStdLog))
+	l.SetLevel(WarnLevel)
+	defer l.SetHandler(handler)
+	defer l.SetLevel(InfoLevel)
+
+	stdlog.SetOutput(l.Writer(InfoLevel))
+	stdlog.SetFlags(0)
+	defer stdlog.SetOutput(os.Stderr)
+	defer stdlog.SetFlags(stdlog.LstdFlags)
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	Error("boom")
+	w.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	assert.Equal(t, "error boom                     \n", buf.String())
+}
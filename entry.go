@@ -1,9 +1,9 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"runtime/debug"
 	"strings"
 	"time"
 )
@@ -24,9 +24,13 @@ type Entry struct {
 	Message   string    `json:"message"`
 	start     time.Time
 	fields    []Fields
-	Env       string `json:"env"`
-	Project   string `json:"project"`
-	Hostname  string `json:"hostname"`
+	viaWriter bool
+	Env       string          `json:"env"`
+	Project   string          `json:"project"`
+	Hostname  string          `json:"hostname"`
+	Name      string          `json:"name,omitempty"`
+	Caller    *Caller         `json:"caller,omitempty"`
+	Context   context.Context `json:"-"`
 }
 
 // NewEntry returns a new entry for `log`.
@@ -47,6 +51,8 @@ func (e *Entry) WithFields(fields Fielder) *Entry {
 		Hostname: e.Hostname,
 		Env:      e.Env,
 		Project:  e.Project,
+		Name:     e.Name,
+		Context:  e.Context,
 	}
 }
 
@@ -60,6 +66,41 @@ func (e *Entry) SetEnvProject(env string, project string) *Entry {
 		Env:      e.Env,
 		Project:  e.Project,
 		Logger:   e.Logger,
+		Name:     e.Name,
+		Context:  e.Context,
+	}
+}
+
+// WithContext returns a new entry with `ctx` attached. The context is passed
+// on to handlers untouched, so they can read deadlines or request scoped values.
+func (e *Entry) WithContext(ctx context.Context) *Entry {
+	return &Entry{
+		Logger:   e.Logger,
+		fields:   e.fields,
+		Hostname: e.Hostname,
+		Env:      e.Env,
+		Project:  e.Project,
+		Name:     e.Name,
+		Context:  ctx,
+	}
+}
+
+// Named returns a new entry with `name` appended to the logger name, separated
+// by a dot, e.g. "db" named "pool" becomes "db.pool". The name is matched against
+// the named levels of the Logger to find the minimum level of the entry.
+func (e *Entry) Named(name string) *Entry {
+	if e.Name != "" {
+		name = e.Name + "." + name
+	}
+
+	return &Entry{
+		Logger:   e.Logger,
+		fields:   e.fields,
+		Hostname: e.Hostname,
+		Env:      e.Env,
+		Project:  e.Project,
+		Name:     name,
+		Context:  e.Context,
 	}
 }
 
@@ -69,20 +110,28 @@ func (e *Entry) WithField(k string, v interface{}) *Entry {
 }
 
 // WithError returns a new entry with the "error" set to `err`.
-// The given error may implement .Fielder, if it does the method
-// will add all its `.Fields()` into the returned entry.
+// The "error" field contains the message, type and causes of the error and
+// a stack trace, see Logger.OmitStack. The given error may implement .Fielder,
+// if it does the method will add all its `.Fields()` into the returned entry.
 func (e *Entry) WithError(err error) *Entry {
-	var errorMap = map[string]interface{}{
-		"trace":   string(debug.Stack()),
-		"message": err.Error(),
-	}
-	ctx := e.WithField("error", errorMap)
+	omitStack := e.Logger != nil && e.Logger.OmitStack
+	ctx := e.WithField("error", errorFields(err, omitStack))
 	if f, ok := err.(Fielder); ok {
 		ctx = ctx.WithFields(f.Fields())
 	}
 	return ctx
 }
 
+// Log logs message at the given level, which may be any level registered with RegisterLevel.
+func (e *Entry) Log(level Level, msg string) {
+	e.Logger.log(level, e, msg)
+}
+
+// Logf logs formatted message at the given level.
+func (e *Entry) Logf(level Level, msg string, v ...interface{}) {
+	e.Log(level, fmt.Sprintf(msg, v...))
+}
+
 /********* Entry simple *********/
 /* WARNING: This part is autogenerated! Do not change! */
 
@@ -235,5 +284,9 @@ func (e *Entry) finalize(level Level, msg string) *Entry {
 		Env:       e.Env,
 		Project:   e.Project,
 		Hostname:  e.Hostname,
+		Name:      e.Name,
+		Caller:    e.Caller,
+		Context:   e.Context,
+		viaWriter: e.viaWriter,
 	}
 }
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -48,3 +49,51 @@ func (ef errFields) Error() string {
 func (ef errFields) Fields() Fields {
 	return Fields{"reason": "timeout"}
 }
+
+type stack []string
+
+func (s stack) Format(f fmt.State, c rune) {
+	for _, frame := range s {
+		fmt.Fprintf(f, "\n%s", frame)
+	}
+}
+
+type errStack struct {
+	msg   string
+	stack stack
+}
+
+func (e *errStack) Error() string {
+	return e.msg
+}
+
+func (e *errStack) StackTrace() stack {
+	return e.stack
+}
+
+func TestEntry_WithError_chain(t *testing.T) {
+	origin := &errStack{"unauthorized", stack{"main.put", "main.upload"}}
+	err := fmt.Errorf("uploading: %w", errors.Join(origin, errors.New("timeout")))
+
+	fields := NewEntry(nil).WithError(err).mergedFields()
+	e := fields["error"].(map[string]interface{})
+
+	assert.Equal(t, "uploading: unauthorized\ntimeout", e["message"])
+	assert.Equal(t, "*fmt.wrapError", e["type"])
+	assert.Equal(t, "\nmain.put\nmain.upload", e["trace"])
+	assert.Equal(t, []map[string]interface{}{
+		{"message": "unauthorized\ntimeout", "type": "*errors.joinError"},
+		{"message": "unauthorized", "type": "*log.errStack"},
+		{"message": "timeout", "type": "*errors.errorString"},
+	}, e["causes"])
+}
+
+func TestEntry_WithError_stack(t *testing.T) {
+	e := NewEntry(nil).WithError(fmt.Errorf("boom")).mergedFields()["error"].(map[string]interface{})
+	assert.Contains(t, e["trace"], "runtime/debug.Stack")
+	assert.NotContains(t, e, "causes")
+
+	l := &Logger{OmitStack: true}
+	e = NewEntry(l).WithError(fmt.Errorf("boom")).mergedFields()["error"].(map[string]interface{})
+	assert.NotContains(t, e, "trace")
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+// maxCauses limits the number of causes recorded for a single error.
+const maxCauses = 32
+
+// errorFields returns the "error" field of `err`. It contains the message and
+// type of the error, the messages and types of all errors wrapped in it
+// (see errors.Unwrap and errors.Join) and a stack trace. The trace is taken
+// from the innermost error implementing the StackTracer interface of
+// github.com/pkg/errors, otherwise the current stack is used unless `omitStack`.
+func errorFields(err error, omitStack bool) map[string]interface{} {
+	var causes []map[string]interface{}
+	trace := stackTrace(err)
+
+	walkCauses(unwrap(err), func(cause error) {
+		causes = append(causes, map[string]interface{}{
+			"message": cause.Error(),
+			"type":    fmt.Sprintf("%T", cause),
+		})
+
+		if t := stackTrace(cause); t != "" {
+			trace = t
+		}
+	})
+
+	m := map[string]interface{}{
+		"message": err.Error(),
+		"type":    fmt.Sprintf("%T", err),
+	}
+
+	if len(causes) > 0 {
+		m["causes"] = causes
+	}
+
+	if trace == "" && !omitStack {
+		trace = string(debug.Stack())
+	}
+
+	if trace != "" {
+		m["trace"] = trace
+	}
+
+	return m
+}
+
+// walkCauses calls fn for each of `errs` and their causes, depth first.
+func walkCauses(errs []error, fn func(error)) {
+	n := 0
+	for len(errs) > 0 && n < maxCauses {
+		err := errs[0]
+		errs = append(unwrap(err), errs[1:]...)
+		fn(err)
+		n++
+	}
+}
+
+// unwrap returns the errors wrapped by `err`, supporting both Unwrap() error
+// and Unwrap() []error.
+func unwrap(err error) []error {
+	switch v := err.(type) {
+	case interface{ Unwrap() []error }:
+		var errs []error
+		for _, e := range v.Unwrap() {
+			if e != nil {
+				errs = append(errs, e)
+			}
+		}
+		return errs
+	default:
+		if e := errors.Unwrap(err); e != nil {
+			return []error{e}
+		}
+		return nil
+	}
+}
+
+// stackTrace returns the stack trace of errors implementing the StackTracer
+// interface of github.com/pkg/errors, i.e. StackTrace() errors.StackTrace.
+// The method is looked up by reflection to avoid the dependency.
+func stackTrace(err error) string {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("%+v", m.Call(nil)[0].Interface())
+}
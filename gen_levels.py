@@ -11,7 +11,7 @@ levels = {
 	"critical": 500,
 	"alert": 550,
 	"fatal": 600,
-	"emergency": 600
+	"emergency": 700
 }
 
 # Function for replacing part of file between marks
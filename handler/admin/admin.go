@@ -0,0 +1,171 @@
+// Package admin implements an HTTP handler for viewing and changing the level
+// of a logger at runtime, optionally reverting the change after a TTL.
+//
+// GET responds with the current level as JSON:
+//
+//	{"level":"info"}
+//
+// PUT or POST changes it, either with a JSON body or with form values:
+//
+//	curl -X PUT -d '{"level":"debug","ttl":"10m"}' -H 'Content-Type: application/json' localhost:8080/log/level
+//	curl -X PUT -d 'level=debug&ttl=10m' localhost:8080/log/level
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// payload of both requests and responses.
+type payload struct {
+	Level    *log.Level `json:"level,omitempty"`
+	TTL      string     `json:"ttl,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Handler implementation.
+type Handler struct {
+	Level *log.AtomicLevel
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	previous log.Level
+	revertAt time.Time
+}
+
+// New handler changing the level of logger `l`.
+func New(l *log.Logger) *Handler {
+	return &Handler{
+		Level: l.AtomicLevel(),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.respond(w, http.StatusOK, h.current())
+	case http.MethodPut, http.MethodPost:
+		h.update(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		h.respond(w, http.StatusMethodNotAllowed, payload{Error: "method not allowed"})
+	}
+}
+
+// update changes the level as requested.
+func (h *Handler) update(w http.ResponseWriter, r *http.Request) {
+	req, err := decode(r)
+	if err != nil {
+		h.respond(w, http.StatusBadRequest, payload{Error: err.Error()})
+		return
+	}
+
+	if req.Level == nil {
+		h.respond(w, http.StatusBadRequest, payload{Error: "missing level"})
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl < 0 {
+			h.respond(w, http.StatusBadRequest, payload{Error: fmt.Sprintf("invalid ttl %q", req.TTL)})
+			return
+		}
+	}
+
+	h.Set(*req.Level, ttl)
+	h.respond(w, http.StatusOK, h.current())
+}
+
+// Set changes the level to `level`. When `ttl` is positive, the level in force
+// before the change is restored after `ttl`. Consecutive temporary changes keep
+// the original level to restore, a change without ttl cancels the pending revert.
+func (h *Handler) Set(level log.Level, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	} else {
+		h.previous = h.Level.Level()
+	}
+
+	h.Level.SetLevel(level)
+
+	if ttl <= 0 {
+		return
+	}
+
+	h.revertAt = time.Now().Add(ttl)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		// the timer was replaced or stopped by a later change
+		if h.timer != timer {
+			return
+		}
+
+		h.Level.SetLevel(h.previous)
+		h.timer = nil
+	})
+	h.timer = timer
+}
+
+// current returns the current state.
+func (h *Handler) current() payload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	level := h.Level.Level()
+	p := payload{Level: &level}
+
+	if h.timer != nil {
+		revertAt := h.revertAt
+		p.RevertAt = &revertAt
+	}
+
+	return p
+}
+
+// respond writes `p` as JSON.
+func (h *Handler) respond(w http.ResponseWriter, code int, p payload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(p)
+}
+
+// decode reads the request from JSON body or form values.
+func decode(r *http.Request) (payload, error) {
+	var p payload
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			return p, fmt.Errorf("invalid request: %s", err)
+		}
+		return p, nil
+	}
+
+	if s := r.FormValue("level"); s != "" {
+		level, err := log.ParseLevel(s)
+		if err != nil {
+			return p, fmt.Errorf("invalid level %q", s)
+		}
+		p.Level = &level
+	}
+
+	p.TTL = r.FormValue("ttl")
+	return p, nil
+}
@@ -0,0 +1,85 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/admin"
+	"github.com/socifi/golog/handler/memory"
+)
+
+func request(h http.Handler, method, body, contentType string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/log/level", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func Test(t *testing.T) {
+	l := &log.Logger{
+		Handler: memory.New(),
+		Level:   log.InfoLevel,
+	}
+
+	h := admin.New(l)
+
+	w := request(h, "GET", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"level":"info"}`+"\n", w.Body.String())
+
+	w = request(h, "PUT", `{"level":"debug"}`, "application/json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"level":"debug"}`+"\n", w.Body.String())
+	assert.Equal(t, log.Level(log.DebugLevel), l.GetLevel())
+
+	w = request(h, "POST", "level=warn", "application/x-www-form-urlencoded")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, log.Level(log.WarnLevel), l.GetLevel())
+
+	w = request(h, "PUT", `{"level":"loud"}`, "application/json")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = request(h, "PUT", "level=debug&ttl=soon", "application/x-www-form-urlencoded")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = request(h, "DELETE", "", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, log.Level(log.WarnLevel), l.GetLevel())
+}
+
+func TestTTL(t *testing.T) {
+	l := &log.Logger{
+		Handler: memory.New(),
+		Level:   log.InfoLevel,
+	}
+
+	h := admin.New(l)
+
+	w := request(h, "PUT", `{"level":"debug","ttl":"50ms"}`, "application/json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"revert_at"`)
+	assert.Equal(t, log.Level(log.DebugLevel), l.GetLevel())
+
+	// a second temporary change keeps the original level to revert to
+	h.Set(log.ErrorLevel, 50*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return l.GetLevel() == log.InfoLevel
+	}, time.Second, 5*time.Millisecond)
+
+	// a permanent change cancels the revert
+	h.Set(log.DebugLevel, 20*time.Millisecond)
+	h.Set(log.WarnLevel, 0)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, log.Level(log.WarnLevel), l.GetLevel())
+}
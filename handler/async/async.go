@@ -0,0 +1,233 @@
+// Package async implements a handler which passes entries to another handler
+// asynchronously through a bounded queue, so slow handlers do not block the
+// goroutines logging.
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// ErrClosed is returned when logging into a closed handler.
+var ErrClosed = errors.New("async: handler closed")
+
+// Policy decides what happens to entries when the queue is full.
+type Policy int
+
+// Policies.
+const (
+	Block      Policy = iota // Block waits until there is space in the queue
+	DropNewest               // DropNewest drops the entry being logged
+	DropOldest               // DropOldest drops the oldest queued entry
+	DropBelow                // DropBelow drops entries below Config.Level and blocks for others
+)
+
+// Config for handler.
+type Config struct {
+	QueueSize   int           // QueueSize is the number of entries waiting for the handler (default: 1024)
+	Workers     int           // Workers is the number of goroutines passing entries to the handler (default: 1)
+	Policy      Policy        // Policy when the queue is full (default: Block)
+	Level       log.Level     // Level of entries which are never dropped with DropBelow
+	ExitTimeout time.Duration // ExitTimeout limits closing the handler on Exit (default: 5s)
+
+	// OnError is called with entries the handler failed to handle,
+	// by default the error is written to the stdlib log.
+	OnError func(*log.Entry, error)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.QueueSize == 0 {
+		c.QueueSize = 1024
+	}
+
+	if c.Workers == 0 {
+		c.Workers = 1
+	}
+
+	if c.ExitTimeout == 0 {
+		c.ExitTimeout = 5 * time.Second
+	}
+
+	if c.OnError == nil {
+		c.OnError = func(e *log.Entry, err error) {
+			log.StdPrintf("log/async: failed to handle %q: %s", e.Message, err)
+		}
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	queue   chan *log.Entry
+	workers sync.WaitGroup
+	dropped atomic.Uint64
+
+	// mu guards closed and the queue from being closed while sending.
+	mu     sync.RWMutex
+	closed bool
+
+	// closeOnce closes the handler once the queue is drained, which may
+	// take more than one call to Close.
+	closeOnce sync.Once
+
+	// pending is the number of entries queued or being handled.
+	pendingMu sync.Mutex
+	pending   int
+	idle      *sync.Cond
+}
+
+// New handler passing entries to `h`. The handler is closed by Exit.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	a := &Handler{
+		Config:  config,
+		Handler: h,
+		queue:   make(chan *log.Entry, config.QueueSize),
+	}
+	a.idle = sync.NewCond(&a.pendingMu)
+
+	for i := 0; i < config.Workers; i++ {
+		a.workers.Add(1)
+		go a.work()
+	}
+
+	log.AddExitHandler(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.ExitTimeout)
+		defer cancel()
+		a.Close(ctx)
+	})
+
+	return a
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return ErrClosed
+	}
+
+	h.add(1)
+
+	select {
+	case h.queue <- e:
+		return nil
+	default:
+	}
+
+	switch {
+	case h.Policy == DropNewest, h.Policy == DropBelow && log.Level(e.Level) < h.Level:
+		h.drop()
+	case h.Policy == DropOldest:
+		for {
+			select {
+			case h.queue <- e:
+				return nil
+			default:
+			}
+
+			select {
+			case <-h.queue:
+				h.drop()
+			default:
+			}
+		}
+	default:
+		h.queue <- e
+	}
+
+	return nil
+}
+
+// Dropped returns the number of entries dropped so far.
+func (h *Handler) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Flush implements log.Flusher, it waits until all entries queued so far
+// are handled and flushes the handler.
+func (h *Handler) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.pendingMu.Lock()
+		for h.pending > 0 {
+			h.idle.Wait()
+		}
+		h.pendingMu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return log.FlushHandler(ctx, h.Handler)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close implements log.Closer, it stops accepting entries, waits until the
+// queued ones are handled and closes the handler.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		var err error
+		h.closeOnce.Do(func() {
+			err = log.CloseHandler(ctx, h.Handler)
+		})
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work passes queued entries to the handler until the queue is closed.
+func (h *Handler) work() {
+	defer h.workers.Done()
+
+	for e := range h.queue {
+		if err := h.Handler.HandleLog(e); err != nil {
+			h.OnError(e, err)
+		}
+		h.add(-1)
+	}
+}
+
+// drop counts a dropped entry.
+func (h *Handler) drop() {
+	h.dropped.Add(1)
+	h.add(-1)
+}
+
+// add changes the number of pending entries by `n`.
+func (h *Handler) add(n int) {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	h.pending += n
+	if h.pending == 0 {
+		h.idle.Broadcast()
+	}
+}
@@ -0,0 +1,166 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/async"
+	"github.com/socifi/golog/handler/memory"
+)
+
+// gate blocks handling until it is opened.
+type gate struct {
+	*memory.Handler
+	entered chan struct{}
+	open    chan struct{}
+	closed  int
+}
+
+func newGate() *gate {
+	return &gate{
+		Handler: memory.New(),
+		entered: make(chan struct{}, 100),
+		open:    make(chan struct{}),
+	}
+}
+
+func (g *gate) HandleLog(e *log.Entry) error {
+	g.entered <- struct{}{}
+	<-g.open
+	return g.Handler.HandleLog(e)
+}
+
+func (g *gate) Close(ctx context.Context) error {
+	g.closed++
+	return nil
+}
+
+func Test(t *testing.T) {
+	h := memory.New()
+	a := async.New(h, &async.Config{Workers: 4})
+
+	l := &log.Logger{
+		Handler: a,
+		Level:   log.InfoLevel,
+	}
+
+	for i := 0; i < 100; i++ {
+		l.Info("upload")
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, a.Flush(ctx))
+	assert.Len(t, h.Entries, 100)
+
+	assert.NoError(t, a.Close(ctx))
+	assert.Equal(t, async.ErrClosed, a.HandleLog(&log.Entry{}))
+	assert.Equal(t, uint64(0), a.Dropped())
+}
+
+func TestPolicy(t *testing.T) {
+	cases := []struct {
+		Policy  async.Policy
+		Entries []string
+		Dropped uint64
+	}{
+		{async.DropNewest, []string{"1", "2", "3"}, 2},
+		{async.DropOldest, []string{"1", "4", "5"}, 2},
+		{async.DropBelow, []string{"1", "2", "3", "5"}, 1},
+	}
+
+	for _, c := range cases {
+		g := newGate()
+		a := async.New(g, &async.Config{
+			QueueSize: 2,
+			Policy:    c.Policy,
+			Level:     log.ErrorLevel,
+		})
+
+		l := &log.Logger{
+			Handler: a,
+			Level:   log.InfoLevel,
+		}
+
+		// the worker takes the first entry and waits, the next two fill the queue
+		l.Info("1")
+		<-g.entered
+		l.Info("2")
+		l.Info("3")
+
+		done := make(chan struct{})
+		go func() {
+			l.Info("4")
+			l.Error("5")
+			close(done)
+		}()
+
+		if c.Policy == async.DropBelow {
+			// "5" waits for space in the queue
+			assert.Eventually(t, func() bool { return a.Dropped() == 1 }, time.Second, time.Millisecond)
+		} else {
+			<-done
+		}
+
+		close(g.open)
+		<-done
+
+		assert.NoError(t, a.Close(context.Background()))
+		assert.Equal(t, c.Dropped, a.Dropped(), c.Entries)
+		assert.Equal(t, c.Entries, g.Handler.Messages())
+	}
+}
+
+func TestFlush_timeout(t *testing.T) {
+	g := newGate()
+	a := async.New(g, &async.Config{})
+	a.HandleLog(&log.Entry{Message: "1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, a.Flush(ctx))
+
+	close(g.open)
+	assert.NoError(t, a.Flush(context.Background()))
+}
+
+func TestClose_timeout(t *testing.T) {
+	g := newGate()
+	a := async.New(g, &async.Config{})
+	a.HandleLog(&log.Entry{Message: "1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, a.Close(ctx))
+	assert.Equal(t, 0, g.closed)
+
+	close(g.open)
+	assert.NoError(t, a.Close(context.Background()))
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, 1, g.closed)
+	assert.Equal(t, []string{"1"}, g.Handler.Messages())
+}
+
+func TestOnError(t *testing.T) {
+	var mu sync.Mutex
+	var failed []string
+
+	a := async.New(log.HandlerFunc(func(e *log.Entry) error {
+		return errors.New("boom")
+	}), &async.Config{
+		OnError: func(e *log.Entry, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, e.Message+": "+err.Error())
+		},
+	})
+
+	a.HandleLog(&log.Entry{Message: "upload"})
+	assert.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, []string{"upload: boom"}, failed)
+}
@@ -0,0 +1,189 @@
+// Package breaker implements a circuit breaker handler, which stops calling a
+// failing handler for a while so that log calls do not pay its timeouts.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// ErrOpen is returned for entries short-circuited without a fallback handler.
+var ErrOpen = errors.New("breaker: circuit open")
+
+// State of the circuit.
+type State int
+
+// States.
+const (
+	Closed   State = iota // Closed passes entries to the handler
+	Open                  // Open short-circuits entries
+	HalfOpen              // HalfOpen lets a single probe entry through
+)
+
+// String implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+// Config for handler.
+type Config struct {
+	Threshold     int                  // Threshold of consecutive failures opening the circuit (default: 5)
+	Cooldown      time.Duration        // Cooldown before an open circuit half-opens (default: 30s)
+	Fallback      log.Handler          // Fallback handles entries while the circuit is open (optional)
+	OnStateChange func(from, to State) // OnStateChange is called on every transition, possibly concurrently (optional)
+	Now           func() time.Time     // Now returns the current time (default: time.Now)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Threshold == 0 {
+		c.Threshold = 5
+	}
+
+	if c.Cooldown == 0 {
+		c.Cooldown = 30 * time.Second
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// New handler wrapping `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:  config,
+		Handler: h,
+	}
+}
+
+// HandleLog implements log.Handler. Threshold consecutive failures open the
+// circuit, entries then go to the Fallback handler, or fail with ErrOpen,
+// without calling the handler. After the Cooldown a single entry probes the
+// handler, closing the circuit on success and opening it again on failure.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	if !h.allow() {
+		if h.Fallback != nil {
+			return h.Fallback.HandleLog(e)
+		}
+		return ErrOpen
+	}
+
+	err := h.Handler.HandleLog(e)
+	h.record(err)
+	return err
+}
+
+// State returns the current state of the circuit.
+func (h *Handler) State() State {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == Open && h.Now().Sub(h.openedAt) >= h.Cooldown {
+		return HalfOpen
+	}
+	return h.state
+}
+
+// Flush implements log.Flusher, flushing the handler and the fallback.
+func (h *Handler) Flush(ctx context.Context) error {
+	return errors.Join(log.FlushHandler(ctx, h.Handler), log.FlushHandler(ctx, h.Fallback))
+}
+
+// Close implements log.Closer, closing the handler and the fallback.
+func (h *Handler) Close(ctx context.Context) error {
+	return errors.Join(log.CloseHandler(ctx, h.Handler), log.CloseHandler(ctx, h.Fallback))
+}
+
+// allow returns whether the entry goes to the handler.
+func (h *Handler) allow() bool {
+	h.mu.Lock()
+	from := h.state
+	if h.state == Open && h.Now().Sub(h.openedAt) >= h.Cooldown {
+		h.state = HalfOpen
+	}
+
+	ok := false
+	switch h.state {
+	case Closed:
+		ok = true
+	case HalfOpen:
+		ok = !h.probing
+		h.probing = true
+	}
+	to := h.state
+	h.mu.Unlock()
+
+	h.notify(from, to)
+	return ok
+}
+
+// record updates the circuit with the result of a call to the handler.
+func (h *Handler) record(err error) {
+	var msg string
+
+	h.mu.Lock()
+	from := h.state
+	if h.state == HalfOpen {
+		h.probing = false
+	}
+
+	if err == nil {
+		h.failures = 0
+		if h.state != Closed {
+			msg = "log/breaker: handler recovered, closing circuit"
+			h.state = Closed
+		}
+	} else {
+		h.failures++
+		if h.state == HalfOpen || (h.state == Closed && h.failures >= h.Threshold) {
+			if h.state == Closed {
+				msg = fmt.Sprintf("log/breaker: %d consecutive failures, opening circuit: %s", h.failures, err)
+			}
+			h.openedAt = h.Now()
+			h.state = Open
+		}
+	}
+	to := h.state
+	h.mu.Unlock()
+
+	if msg != "" {
+		log.StdPrintf("%s", msg)
+	}
+	h.notify(from, to)
+}
+
+// notify calls OnStateChange if the state changed. It is called without
+// holding mu, so the callback may log or call State.
+func (h *Handler) notify(from, to State) {
+	if from != to && h.OnStateChange != nil {
+		h.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,121 @@
+package breaker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/breaker"
+	"github.com/socifi/golog/handler/memory"
+)
+
+func Test(t *testing.T) {
+	var transitions []string
+	var calls int
+	down := true
+	now := time.Unix(0, 0)
+	m := memory.New()
+
+	h := breaker.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls++
+		if down {
+			return errors.New("connection timeout")
+		}
+		return m.HandleLog(e)
+	}), &breaker.Config{
+		Threshold: 2,
+		Cooldown:  time.Minute,
+		Now:       func() time.Time { return now },
+		OnStateChange: func(from, to breaker.State) {
+			transitions = append(transitions, from.String()+">"+to.String())
+		},
+	})
+
+	e := &log.Entry{Message: "hello"}
+
+	assert.EqualError(t, h.HandleLog(e), "connection timeout")
+	assert.Equal(t, breaker.Closed, h.State())
+	assert.EqualError(t, h.HandleLog(e), "connection timeout")
+	assert.Equal(t, breaker.Open, h.State())
+
+	assert.Equal(t, breaker.ErrOpen, h.HandleLog(e))
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, breaker.HalfOpen, h.State())
+	assert.EqualError(t, h.HandleLog(e), "connection timeout")
+	assert.Equal(t, breaker.Open, h.State())
+	assert.Equal(t, breaker.ErrOpen, h.HandleLog(e))
+	assert.Equal(t, 3, calls)
+
+	now = now.Add(time.Minute)
+	down = false
+	assert.NoError(t, h.HandleLog(e))
+	assert.Equal(t, breaker.Closed, h.State())
+	assert.Len(t, m.Entries, 1)
+
+	assert.Equal(t, []string{
+		"closed>open",
+		"open>half-open",
+		"half-open>open",
+		"open>half-open",
+		"half-open>closed",
+	}, transitions)
+}
+
+func TestFallback(t *testing.T) {
+	var calls int
+	fallback := memory.New()
+
+	h := breaker.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls++
+		return errors.New("connection timeout")
+	}), &breaker.Config{
+		Threshold: 1,
+		Fallback:  fallback,
+	})
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("1")
+	l.Info("2")
+	l.Info("3")
+
+	assert.Equal(t, 1, calls)
+	assert.Len(t, fallback.Entries, 2)
+	assert.Equal(t, "2", fallback.Entries[0].Message)
+}
+
+func TestOnStateChange_reentrant(t *testing.T) {
+	var states []breaker.State
+	var h *breaker.Handler
+
+	h = breaker.New(log.HandlerFunc(func(e *log.Entry) error {
+		return errors.New("connection timeout")
+	}), &breaker.Config{
+		Threshold: 1,
+		OnStateChange: func(from, to breaker.State) {
+			states = append(states, h.State())
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		h.HandleLog(&log.Entry{Message: "hello"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("OnStateChange deadlocked")
+	}
+
+	assert.Equal(t, []breaker.State{breaker.Open}, states)
+}
@@ -0,0 +1,175 @@
+// Package dedup implements a handler which collapses identical entries, e.g.
+// the same error logged by a crash loop, into a single summary entry.
+package dedup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// Config for handler.
+type Config struct {
+	Window  time.Duration    // Window in which identical entries are collapsed (default: 10s)
+	Fields  []string         // Fields which must be equal too, besides level and message
+	MaxKeys int              // MaxKeys tracked at once, other entries pass (default: 1000)
+	Now     func() time.Time // Now returns the current time (default: time.Now)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Window == 0 {
+		c.Window = 10 * time.Second
+	}
+
+	if c.MaxKeys == 0 {
+		c.MaxKeys = 1000
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// group of identical entries.
+type group struct {
+	key     string
+	entry   *log.Entry
+	repeats int
+	first   time.Time
+	last    time.Time
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	mu     sync.Mutex
+	groups map[string]*group
+	order  []*group
+}
+
+// New handler deduplicating entries passed to `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:  config,
+		Handler: h,
+		groups:  make(map[string]*group),
+	}
+}
+
+// HandleLog implements log.Handler. The first of identical entries is passed
+// on, repeats within the Window are counted and emitted as a copy of the first
+// entry with the "repeat_count", "first_seen" and "last_seen" fields once the
+// Window is over. Summaries are emitted before the next entry, or on Flush.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	now := h.Now()
+	key := h.key(e)
+
+	h.mu.Lock()
+	summaries := h.expire(now)
+	g, repeated := h.groups[key]
+	if repeated {
+		g.repeats++
+		g.last = now
+	} else if len(h.groups) < h.MaxKeys {
+		g = &group{key: key, entry: e, first: now, last: now}
+		h.groups[key] = g
+		h.order = append(h.order, g)
+	}
+	h.mu.Unlock()
+
+	err := h.emit(summaries)
+	if !repeated {
+		err = errors.Join(err, h.Handler.HandleLog(e))
+	}
+	return err
+}
+
+// Flush implements log.Flusher, it emits all pending summaries and flushes the handler.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	summaries := h.expire(time.Time{})
+	h.mu.Unlock()
+
+	return errors.Join(h.emit(summaries), log.FlushHandler(ctx, h.Handler))
+}
+
+// Close implements log.Closer, it emits all pending summaries and closes the handler.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	summaries := h.expire(time.Time{})
+	h.mu.Unlock()
+
+	return errors.Join(h.emit(summaries), log.CloseHandler(ctx, h.Handler))
+}
+
+// key returns the identity of `e`.
+func (h *Handler) key(e *log.Entry) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(e.Level))
+	b.WriteByte(0)
+	b.WriteString(e.Message)
+	for _, name := range h.Fields {
+		b.WriteByte(0)
+		if v, ok := e.Fields[name]; ok {
+			fmt.Fprint(&b, v)
+		}
+	}
+	return b.String()
+}
+
+// expire removes the groups whose window is over at `now`, all of them for
+// zero `now`, and returns the summaries of the repeated ones. Caller must hold mu.
+func (h *Handler) expire(now time.Time) []*log.Entry {
+	var summaries []*log.Entry
+
+	for len(h.order) > 0 {
+		g := h.order[0]
+		if !now.IsZero() && now.Sub(g.first) < h.Window {
+			break
+		}
+
+		h.order = h.order[1:]
+		delete(h.groups, g.key)
+
+		if g.repeats > 0 {
+			summaries = append(summaries, summary(g))
+		}
+	}
+
+	return summaries
+}
+
+// summary returns the summary entry of `g`.
+func summary(g *group) *log.Entry {
+	e := *g.entry
+	e.Fields = make(log.Fields, len(g.entry.Fields)+3)
+	for k, v := range g.entry.Fields {
+		e.Fields[k] = v
+	}
+	e.Fields["repeat_count"] = g.repeats
+	e.Fields["first_seen"] = g.first
+	e.Fields["last_seen"] = g.last
+	e.Timestamp = g.last
+	return &e
+}
+
+// emit passes the summary entries to the handler.
+func (h *Handler) emit(entries []*log.Entry) error {
+	var errs []error
+	for _, e := range entries {
+		if err := h.Handler.HandleLog(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
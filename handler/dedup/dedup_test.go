@@ -0,0 +1,70 @@
+package dedup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/dedup"
+	"github.com/socifi/golog/handler/memory"
+)
+
+func Test(t *testing.T) {
+	start := time.Unix(0, 0)
+	now := start
+	h := memory.New()
+
+	d := dedup.New(h, &dedup.Config{
+		Window: 10 * time.Second,
+		Fields: []string{"worker"},
+		Now:    func() time.Time { return now },
+	})
+
+	l := &log.Logger{
+		Handler: d,
+		Level:   log.InfoLevel,
+	}
+
+	for i := 0; i < 4; i++ {
+		l.WithField("worker", 1).WithError(errors.New("boom")).Error("crashed")
+		now = now.Add(time.Second)
+	}
+	l.WithField("worker", 2).Error("crashed")
+	l.WithField("worker", 1).Warn("crashed")
+	assert.Equal(t, []string{"crashed", "crashed", "crashed"}, h.Messages())
+
+	now = start.Add(10 * time.Second)
+	l.Info("tick")
+	assert.Equal(t, []string{"crashed", "crashed", "crashed", "crashed", "tick"}, h.Messages())
+
+	e := h.Entries[3]
+	assert.Equal(t, int(log.ErrorLevel), e.Level)
+	assert.Equal(t, 1, e.Fields["worker"])
+	assert.Equal(t, 3, e.Fields["repeat_count"])
+	assert.Equal(t, start, e.Fields["first_seen"])
+	assert.Equal(t, start.Add(3*time.Second), e.Fields["last_seen"])
+	assert.Contains(t, e.Fields, "error")
+	assert.NotContains(t, h.Entries[0].Fields, "repeat_count")
+}
+
+func TestFlush(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, &dedup.Config{})
+
+	l := &log.Logger{
+		Handler: d,
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("hello")
+	l.Info("hello")
+	l.Info("world")
+	assert.NoError(t, d.Flush(context.Background()))
+
+	assert.Equal(t, []string{"hello", "world", "hello"}, h.Messages())
+	assert.Equal(t, 1, h.Entries[2].Fields["repeat_count"])
+}
@@ -3,9 +3,9 @@
 package es
 
 import (
+	"context"
 	"io"
 	//	"fmt"
-	stdlog "log"
 	"sync"
 	"time"
 
@@ -17,7 +17,6 @@ import (
 // TODO(tj): allow dumping logs to stderr on timeout
 // TODO(tj): allow custom format that does not include .fields etc
 // TODO(tj): allow interval flushes
-// TODO(tj): allow explicit Flush() (for Lambda where you have to flush at the end of function)
 
 // Elasticsearch interface.
 type Elasticsearch interface {
@@ -64,7 +63,7 @@ func New(config *Config) *Handler {
 	}
 
 	log.AddExitHandler(func() {
-		h.flush()
+		h.Flush(context.Background())
 	})
 	return h
 }
@@ -86,25 +85,40 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 
 	if h.batch.Size() >= h.BufferSize {
 		h.flush()
-		h.batch = nil
 	}
 
 	return nil
 }
 
-// flush the given `batch` asynchronously.
-func (h *Handler) flush() {
+// Flush implements log.Flusher, sending the buffered logs, e.g. at the end
+// of a Lambda function. The bulk request itself is not cancelled by `ctx`.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.flush()
+}
+
+// Close implements log.Closer, it flushes the buffered logs.
+func (h *Handler) Close(ctx context.Context) error {
+	return h.Flush(ctx)
+}
+
+// flush the current batch, the caller must hold mu.
+func (h *Handler) flush() error {
 	if h.batch == nil {
-		return
+		return nil
 	}
 	size := h.batch.Size()
 	start := time.Now()
-	stdlog.Printf("log/elastic: flushing %d logs", size)
+	log.StdPrintf("log/elastic: flushing %d logs", size)
 
-	if err := h.batch.Flush(); err != nil {
-		stdlog.Printf("log/elastic: failed to flush %d logs: %s", size, err)
-		return
+	err := h.batch.Flush()
+	h.batch = nil
+	if err != nil {
+		log.StdPrintf("log/elastic: failed to flush %d logs: %s", size, err)
+		return err
 	}
 
-	stdlog.Printf("log/elastic: flushed %d logs in %s", size, time.Since(start))
+	log.StdPrintf("log/elastic: flushed %d logs in %s", size, time.Since(start))
+	return nil
 }
@@ -0,0 +1,154 @@
+// Package failover implements a handler which sends entries to a primary
+// handler and fails over to a secondary one, e.g. a local file, while the
+// primary is failing or too slow.
+package failover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// ErrTimeout is returned when the primary handler exceeds the latency budget.
+var ErrTimeout = errors.New("failover: latency budget exceeded")
+
+// Config for handler.
+type Config struct {
+	// LatencyBudget is the time the primary handler has to handle an entry,
+	// slower calls count as failures (default: no limit). The late call is
+	// not cancelled, so the entry may end up in both handlers.
+	LatencyBudget time.Duration
+
+	// ProbeInterval is how often an entry is sent to a failed primary handler
+	// to find out if it works again (default: 30s).
+	ProbeInterval time.Duration
+
+	// Now returns the current time (default: time.Now).
+	Now func() time.Time
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.ProbeInterval == 0 {
+		c.ProbeInterval = 30 * time.Second
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Primary   log.Handler
+	Secondary log.Handler
+
+	mu       sync.Mutex
+	failed   bool
+	probedAt time.Time
+}
+
+// New handler.
+func New(primary, secondary log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:    config,
+		Primary:   primary,
+		Secondary: secondary,
+	}
+}
+
+// HandleLog implements log.Handler. While the primary handler works, entries
+// only go there. When it fails, the entry and all following ones go to the
+// secondary handler, except for one entry every ProbeInterval which probes
+// the primary handler and switches back to it on success.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	if !h.usePrimary() {
+		return h.Secondary.HandleLog(e)
+	}
+
+	err := h.primary(e)
+
+	h.mu.Lock()
+	switched := h.failed != (err != nil)
+	h.failed = err != nil
+	if h.failed {
+		h.probedAt = h.Now()
+	}
+	h.mu.Unlock()
+
+	if err == nil {
+		if switched {
+			log.StdPrintf("log/failover: primary handler recovered")
+		}
+		return nil
+	}
+
+	if switched {
+		log.StdPrintf("log/failover: primary handler failed, switching to secondary: %s", err)
+	}
+
+	return h.Secondary.HandleLog(e)
+}
+
+// Healthy returns whether entries currently go to the primary handler.
+func (h *Handler) Healthy() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.failed
+}
+
+// Flush implements log.Flusher, flushing both handlers.
+func (h *Handler) Flush(ctx context.Context) error {
+	return errors.Join(log.FlushHandler(ctx, h.Primary), log.FlushHandler(ctx, h.Secondary))
+}
+
+// Close implements log.Closer, closing both handlers.
+func (h *Handler) Close(ctx context.Context) error {
+	return errors.Join(log.CloseHandler(ctx, h.Primary), log.CloseHandler(ctx, h.Secondary))
+}
+
+// usePrimary returns whether the entry goes to the primary handler. Only one
+// entry per ProbeInterval probes a failed primary handler.
+func (h *Handler) usePrimary() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.failed {
+		return true
+	}
+
+	now := h.Now()
+	if now.Sub(h.probedAt) < h.ProbeInterval {
+		return false
+	}
+
+	h.probedAt = now
+	return true
+}
+
+// primary passes `e` to the primary handler within the latency budget.
+func (h *Handler) primary(e *log.Entry) error {
+	if h.LatencyBudget <= 0 {
+		return h.Primary.HandleLog(e)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Primary.HandleLog(e)
+	}()
+
+	timer := time.NewTimer(h.LatencyBudget)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,79 @@
+package failover_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/failover"
+	"github.com/socifi/golog/handler/memory"
+)
+
+func Test(t *testing.T) {
+	var down bool
+	now := time.Unix(0, 0)
+	primary := memory.New()
+	secondary := memory.New()
+
+	h := failover.New(log.HandlerFunc(func(e *log.Entry) error {
+		if down {
+			return errors.New("cluster maintenance")
+		}
+		return primary.HandleLog(e)
+	}), secondary, &failover.Config{
+		ProbeInterval: time.Minute,
+		Now:           func() time.Time { return now },
+	})
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("1")
+	assert.True(t, h.Healthy())
+
+	down = true
+	l.Info("2")
+	assert.False(t, h.Healthy())
+
+	now = now.Add(30 * time.Second)
+	down = false
+	l.Info("3")
+
+	now = now.Add(30 * time.Second)
+	l.Info("4")
+	assert.True(t, h.Healthy())
+	l.Info("5")
+
+	assert.Equal(t, []string{"1", "4", "5"}, primary.Messages())
+	assert.Equal(t, []string{"2", "3"}, secondary.Messages())
+}
+
+func TestLatencyBudget(t *testing.T) {
+	secondary := memory.New()
+
+	h := failover.New(log.HandlerFunc(func(e *log.Entry) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}), secondary, &failover.Config{
+		LatencyBudget: 10 * time.Millisecond,
+	})
+
+	assert.NoError(t, h.HandleLog(&log.Entry{Message: "slow"}))
+	assert.False(t, h.Healthy())
+	assert.Equal(t, []string{"slow"}, secondary.Messages())
+}
+
+func TestSecondaryError(t *testing.T) {
+	h := failover.New(log.HandlerFunc(func(e *log.Entry) error {
+		return errors.New("cluster maintenance")
+	}), log.HandlerFunc(func(e *log.Entry) error {
+		return errors.New("disk full")
+	}), &failover.Config{})
+
+	assert.EqualError(t, h.HandleLog(&log.Entry{}), "disk full")
+}
@@ -0,0 +1,387 @@
+// Package file implements a rotating log file writer for the json, logfmt and
+// text handlers. Files are rotated by size and time, backups are optionally
+// compressed and removed by count and age, and the file can be reopened on
+// SIGHUP for logrotate compatibility.
+package file
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// backupFormat is the time format in the names of backups.
+const backupFormat = "2006-01-02T15-04-05.000"
+
+// ErrClosed is returned for writes after Close.
+var ErrClosed = errors.New("file: writer closed")
+
+// Config for writer.
+type Config struct {
+	MaxSize        int64            // MaxSize of the file in bytes before it is rotated (default: 100MB, negative disables it)
+	Interval       time.Duration    // Interval of time based rotation, aligned to UTC (default: disabled)
+	MaxBackups     int              // MaxBackups kept (default: all)
+	MaxAge         time.Duration    // MaxAge of kept backups (default: no limit)
+	Compress       bool             // Compress backups with gzip
+	ReopenOnSignal bool             // ReopenOnSignal reopens the file on SIGHUP, e.g. after logrotate moved it
+	Perm           os.FileMode      // Perm of created files (default: 0644)
+	Now            func() time.Time // Now returns the current time (default: time.Now)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.MaxSize == 0 {
+		c.MaxSize = 100 << 20
+	}
+
+	if c.Perm == 0 {
+		c.Perm = 0644
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// Writer implementation.
+type Writer struct {
+	*Config
+	Path string
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	period time.Time
+	closed bool
+
+	mill    chan struct{}
+	signals chan os.Signal
+	wg      sync.WaitGroup
+}
+
+// New writer appending to file `path`.
+func New(path string, config *Config) (*Writer, error) {
+	config.defaults()
+	w := &Writer{
+		Config: config,
+		Path:   path,
+		mill:   make(chan struct{}, 1),
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.millRun()
+
+	if w.ReopenOnSignal {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, syscall.SIGHUP)
+		w.wg.Add(1)
+		go w.signalRun()
+	}
+
+	return w, nil
+}
+
+// Write implements io.Writer, rotating the file first when the write would
+// exceed MaxSize or a new Interval started. When the rotation fails, the
+// write goes to the current file and the rotation is retried with the next one.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	if w.due(len(p)) {
+		if err := w.rotate(); err != nil {
+			log.StdPrintf("log/file: error rotating %s: %s", w.Path, err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the file to a backup and opens a new one.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+	return w.rotate()
+}
+
+// Reopen closes and opens the file again, so writes continue in a new file
+// when it was moved away.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// Flush commits the file to stable storage, the json, logfmt and text
+// handlers call it on Flush.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+	return w.file.Sync()
+}
+
+// Close implements io.Closer, it closes the file and waits until backups are
+// compressed and cleaned up.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.file.Close()
+	w.mu.Unlock()
+
+	if w.signals != nil {
+		signal.Stop(w.signals)
+		close(w.signals)
+	}
+	close(w.mill)
+	w.wg.Wait()
+	return err
+}
+
+// open opens the file for appending, caller must hold mu.
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.Path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(w.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.Perm)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.period = w.Now()
+	if w.size > 0 {
+		w.period = info.ModTime()
+	}
+	if w.Interval > 0 {
+		w.period = w.period.Truncate(w.Interval)
+	}
+	return nil
+}
+
+// due returns whether the file must be rotated before writing `n` bytes,
+// caller must hold mu.
+func (w *Writer) due(n int) bool {
+	if w.size == 0 {
+		return false
+	}
+
+	if w.MaxSize > 0 && w.size+int64(n) > w.MaxSize {
+		return true
+	}
+
+	return w.Interval > 0 && w.Now().Truncate(w.Interval).After(w.period)
+}
+
+// rotate renames the file to a backup and opens a new one. When the rename
+// fails, the file is opened again, so that writes continue. Caller must hold mu.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+
+	err := os.Rename(w.Path, w.backupName(w.Now()))
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	if openErr := w.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case w.mill <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// prefixExt returns the parts of backup names around the timestamp.
+func (w *Writer) prefixExt() (string, string) {
+	ext := filepath.Ext(w.Path)
+	return strings.TrimSuffix(w.Path, ext) + "-", ext
+}
+
+// backupName returns the name of the backup rotated at `t`. The time is moved
+// on by a millisecond while the name is taken, so backups are not overwritten.
+func (w *Writer) backupName(t time.Time) string {
+	prefix, ext := w.prefixExt()
+	for {
+		name := prefix + t.UTC().Format(backupFormat) + ext
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// exists returns whether file `path` exists.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// backup file.
+type backup struct {
+	path string
+	time time.Time
+}
+
+// backups returns the backups of the file, the newest first.
+func (w *Writer) backups() ([]backup, error) {
+	prefix, ext := w.prefixExt()
+	dir := filepath.Dir(w.Path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		name := strings.TrimSuffix(path, ".gz")
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		t, err := time.Parse(backupFormat, name[len(prefix):len(name)-len(ext)])
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: path, time: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+// millRun compresses and removes backups after rotations.
+func (w *Writer) millRun() {
+	defer w.wg.Done()
+	for range w.mill {
+		if err := w.millRunOnce(); err != nil {
+			log.StdPrintf("log/file: %s", err)
+		}
+	}
+}
+
+// millRunOnce removes backups over MaxBackups or older than MaxAge and
+// compresses the rest.
+func (w *Writer) millRunOnce() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, b := range backups {
+		if (w.MaxBackups > 0 && i >= w.MaxBackups) || (w.MaxAge > 0 && w.Now().Sub(b.time) > w.MaxAge) {
+			errs = append(errs, os.Remove(b.path))
+			continue
+		}
+
+		if w.Compress && !strings.HasSuffix(b.path, ".gz") {
+			errs = append(errs, compress(b.path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// signalRun reopens the file on SIGHUP.
+func (w *Writer) signalRun() {
+	defer w.wg.Done()
+	for range w.signals {
+		if err := w.Reopen(); err != nil && err != ErrClosed {
+			log.StdPrintf("log/file: error reopening %s: %s", w.Path, err)
+		}
+	}
+}
+
+// compress gzips file `path` and removes it.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return fmt.Errorf("compressing %s: %w", path, err)
+	}
+
+	if err := errors.Join(gz.Close(), dst.Close()); err != nil {
+		os.Remove(path + ".gz")
+		return fmt.Errorf("compressing %s: %w", path, err)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,198 @@
+package file_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/file"
+	"github.com/socifi/golog/handler/logfmt"
+)
+
+func read(t *testing.T, path string) string {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func names(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	var v []string
+	for _, e := range entries {
+		v = append(v, e.Name())
+	}
+	sort.Strings(v)
+	return v
+}
+
+func Test(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	w, err := file.New(path, &file.Config{
+		MaxSize:    10,
+		MaxBackups: 2,
+		Now:        func() time.Time { return now },
+	})
+	assert.NoError(t, err)
+
+	for _, s := range []string{"12345\n", "abcde\n", "ABCDE\n", "xyz\n", "0123456789\n"} {
+		now = now.Add(time.Second)
+		_, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2020-01-01T00-00-03.000.log",
+		"app-2020-01-01T00-00-05.000.log",
+		"app.log",
+	}, names(t, dir))
+	assert.Equal(t, "abcde\n", read(t, filepath.Join(dir, "app-2020-01-01T00-00-03.000.log")))
+	assert.Equal(t, "ABCDE\nxyz\n", read(t, filepath.Join(dir, "app-2020-01-01T00-00-05.000.log")))
+	assert.Equal(t, "0123456789\n", read(t, path))
+
+	_, err = w.Write([]byte("closed"))
+	assert.Equal(t, file.ErrClosed, err)
+}
+
+func TestInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	w, err := file.New(path, &file.Config{
+		Interval: 24 * time.Hour,
+		Compress: true,
+		MaxAge:   48 * time.Hour,
+		Now:      func() time.Time { return now },
+	})
+	assert.NoError(t, err)
+
+	l := &log.Logger{
+		Handler: logfmt.New(w),
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("first")
+	now = now.Add(2 * time.Hour)
+	l.Info("second")
+	assert.NoError(t, l.Close(context.Background()))
+
+	assert.Equal(t, []string{"app-2020-01-02T01-00-00.000.log.gz", "app.log"}, names(t, dir))
+
+	f, err := os.Open(filepath.Join(dir, "app-2020-01-02T01-00-00.000.log.gz"))
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	b, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "message=first")
+	assert.Contains(t, read(t, path), "message=second")
+
+	w, err = file.New(path, &file.Config{
+		MaxAge: 48 * time.Hour,
+		Now:    func() time.Time { return now.Add(72 * time.Hour) },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Rotate())
+	assert.NoError(t, w.Close())
+	assert.Equal(t, []string{"app-2020-01-05T01-00-00.000.log", "app.log"}, names(t, dir))
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := file.New(path, &file.Config{ReopenOnSignal: true})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("1\n"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, w.Reopen())
+	_, err = w.Write([]byte("2\n"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1\n", read(t, path+".1"))
+	assert.Equal(t, "2\n", read(t, path))
+
+	assert.NoError(t, os.Rename(path, path+".2"))
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("SIGHUP not supported")
+	}
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestRotateError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+
+	w, err := file.New(path, &file.Config{MaxSize: 5})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("AAAAA"))
+	assert.NoError(t, err)
+
+	// the directory is replaced by a file, so both rename and reopen fail
+	assert.NoError(t, os.RemoveAll(dir))
+	assert.NoError(t, os.WriteFile(dir, nil, 0644))
+	_, err = w.Write([]byte("BBBBB"))
+	assert.Error(t, err)
+
+	assert.NoError(t, os.Remove(dir))
+	_, err = w.Write([]byte("CCCCC"))
+	assert.NoError(t, err)
+	assert.Equal(t, "CCCCC", read(t, path))
+}
+
+func TestBackupName(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	w, err := file.New(path, &file.Config{
+		MaxSize: 5,
+		Now:     func() time.Time { return now },
+	})
+	assert.NoError(t, err)
+
+	for _, s := range []string{"AAAAA", "BBBBB", "CCCCC"} {
+		_, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2020-01-01T00-00-00.000.log",
+		"app-2020-01-01T00-00-00.001.log",
+		"app.log",
+	}, names(t, dir))
+	assert.Equal(t, "AAAAA", read(t, filepath.Join(dir, "app-2020-01-01T00-00-00.000.log")))
+	assert.Equal(t, "BBBBB", read(t, filepath.Join(dir, "app-2020-01-01T00-00-00.001.log")))
+	assert.Equal(t, "CCCCC", read(t, path))
+}
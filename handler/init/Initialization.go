@@ -2,6 +2,7 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"time"
@@ -9,12 +10,15 @@ import (
 	lg "github.com/socifi/golog"
 	"github.com/socifi/golog/handler/discard"
 	"github.com/socifi/golog/handler/es"
+	"github.com/socifi/golog/handler/file"
 	"github.com/socifi/golog/handler/json"
 	"github.com/socifi/golog/handler/kinesis"
 	"github.com/socifi/golog/handler/logfmt"
 	"github.com/socifi/golog/handler/memory"
 	"github.com/socifi/golog/handler/multi"
 	"github.com/socifi/golog/handler/papertrail"
+	"github.com/socifi/golog/handler/route"
+	"github.com/socifi/golog/handler/text"
 	"github.com/tj/go-elastic"
 )
 
@@ -23,29 +27,58 @@ type Log lg.Interface
 // LogConfig contains all needed information for logger initialization
 type Config struct {
 	LogLevel string      `json:"logLevel"`
+	Levels   string      `json:"levels,omitempty"` // Levels of named loggers, e.g. "db=debug,http=warn"
 	Handlers interface{} `json:"handlers,omitempty"`
 	Context  lg.Fields   `json:"context"`
 	Env      string      `json:"env"`
 	Project  string      `json:"project"`
 }
 
+// openFile opens the rotating log file given in settings, stdout if there is none
+func openFile(s map[string]interface{}) (io.Writer, error) {
+	path, _ := s["file"].(string)
+	if path == "stdout" || path == "" {
+		return os.Stdout, nil
+	}
+
+	var c file.Config
+	if maxSize, ok := s["maxSize"].(float64); ok {
+		c.MaxSize = int64(maxSize * (1 << 20))
+	}
+	if maxBackups, ok := s["maxBackups"].(float64); ok {
+		c.MaxBackups = int(maxBackups)
+	}
+	if maxAge, ok := s["maxAge"].(float64); ok {
+		c.MaxAge = time.Duration(maxAge * float64(24*time.Hour))
+	}
+	if rotate, ok := s["rotate"].(string); ok {
+		interval, err := time.ParseDuration(rotate)
+		if err != nil {
+			return nil, err
+		}
+		c.Interval = interval
+	}
+	if compress, ok := s["compress"].(bool); ok {
+		c.Compress = compress
+	}
+	if reopen, ok := s["reopen"].(bool); ok {
+		c.ReopenOnSignal = reopen
+	}
+
+	return file.New(path, &c)
+}
+
 // initJSON initializes new JSON log with given settings
 func initJSON(settings interface{}) (lg.Handler, error) {
 	s, ok := settings.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("Error converting json handler data to map")
 	}
-	var file *os.File
-	var err error
-	if s["file"] == "stdout" || s["file"] == "" {
-		file = os.Stdout
-	} else {
-		file, err = os.Create(s["file"].(string))
-		if err != nil {
-			return nil, err
-		}
+	w, err := openFile(s)
+	if err != nil {
+		return nil, err
 	}
-	return json.New(file), nil
+	return json.New(w), nil
 }
 
 // initElastic initializes new elastic log with given settings
@@ -87,17 +120,28 @@ func initLogfmt(settings interface{}) (lg.Handler, error) {
 	if !ok {
 		return nil, fmt.Errorf("Error converting logfmt handler data to map")
 	}
-	var file *os.File
-	var err error
-	if s["file"] == "stdout" || s["file"] == "" {
-		file = os.Stdout
-	} else {
-		file, err = os.Create(s["file"].(string))
-		if err != nil {
-			return nil, err
-		}
+	w, err := openFile(s)
+	if err != nil {
+		return nil, err
 	}
-	return logfmt.New(file), nil
+	return logfmt.New(w), nil
+}
+
+// initText initializes new text log with given settings
+func initText(settings interface{}) (lg.Handler, error) {
+	s, ok := settings.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Error converting text handler data to map")
+	}
+	w, err := openFile(s)
+	if err != nil {
+		return nil, err
+	}
+	h := text.New(w)
+	if color, ok := s["color"].(bool); ok {
+		h.Color = color
+	}
+	return h, nil
 }
 
 // initMemory initializes new in memory log
@@ -144,6 +188,25 @@ func initPapertrail(settings interface{}) (lg.Handler, error) {
 	return papertrail.New(&c), nil
 }
 
+// withFilter wraps handler in a route filter when its settings contain a "filter" expression
+func withFilter(settings interface{}, handler lg.Handler) (lg.Handler, error) {
+	s, ok := settings.(map[string]interface{})
+	if !ok || s["filter"] == nil {
+		return handler, nil
+	}
+
+	expr, ok := s["filter"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Handler filter must be a string expression")
+	}
+
+	p, err := route.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return route.Filter(p, handler), nil
+}
+
 // Init initializes logger with values from LogConfig structure
 func Init(config Config) (Log, error) {
 	h, ok := config.Handlers.(map[string]interface{})
@@ -154,6 +217,9 @@ func Init(config Config) (Log, error) {
 	var handlers []lg.Handler
 	if (h["json"]) != nil {
 		handler, err := initJSON(h["json"])
+		if err == nil {
+			handler, err = withFilter(h["json"], handler)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -162,6 +228,9 @@ func Init(config Config) (Log, error) {
 
 	if (h["elastic"]) != nil {
 		handler, err := initElastic(h["elastic"])
+		if err == nil {
+			handler, err = withFilter(h["elastic"], handler)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -174,6 +243,9 @@ func Init(config Config) (Log, error) {
 
 	if (h["kinesis"]) != nil {
 		handler, err := initKinesis(h["kinesis"])
+		if err == nil {
+			handler, err = withFilter(h["kinesis"], handler)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -182,6 +254,20 @@ func Init(config Config) (Log, error) {
 
 	if (h["logfmt"]) != nil {
 		handler, err := initLogfmt(h["logfmt"])
+		if err == nil {
+			handler, err = withFilter(h["logfmt"], handler)
+		}
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, handler)
+	}
+
+	if (h["text"]) != nil {
+		handler, err := initText(h["text"])
+		if err == nil {
+			handler, err = withFilter(h["text"], handler)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -194,6 +280,9 @@ func Init(config Config) (Log, error) {
 
 	if (h["papertrail"]) != nil {
 		handler, err := initPapertrail(h["papertrail"])
+		if err == nil {
+			handler, err = withFilter(h["papertrail"], handler)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -202,6 +291,9 @@ func Init(config Config) (Log, error) {
 
 	lg.SetHandler(multi.New(handlers...))
 	lg.SetLevelFromString(config.LogLevel)
+	if err := lg.SetNamedLevels(config.Levels); err != nil {
+		return nil, err
+	}
 	lg.WithFields(config.Context)
 
 	logger := lg.SetEnvProject(config.Env, config.Project)
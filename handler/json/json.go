@@ -2,6 +2,7 @@
 package json
 
 import (
+	"context"
 	j "encoding/json"
 	"io"
 	"os"
@@ -17,12 +18,14 @@ var Default = New(os.Stderr)
 type Handler struct {
 	*j.Encoder
 	mu sync.Mutex
+	w  io.Writer
 }
 
 // New handler.
 func New(w io.Writer) *Handler {
 	return &Handler{
 		Encoder: j.NewEncoder(w),
+		w:       w,
 	}
 }
 
@@ -32,3 +35,17 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	defer h.mu.Unlock()
 	return h.Encoder.Encode(e)
 }
+
+// Flush implements log.Flusher, see log.FlushWriter.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.FlushWriter(h.w)
+}
+
+// Close implements log.Closer, see log.CloseWriter.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.CloseWriter(h.w)
+}
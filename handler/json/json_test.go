@@ -2,6 +2,8 @@ package json_test
 
 import (
 	"bytes"
+	"context"
+	"os"
 	"testing"
 	"time"
 
@@ -32,3 +34,38 @@ func Test(t *testing.T) {
 
 	assert.Equal(t, expected, buf.String())
 }
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler:      json.New(&buf),
+		Level:        log.InfoLevel,
+		ReportCaller: true,
+	}
+
+	l.Info("hello")
+
+	assert.Regexp(t, `"caller":\{"file":".*/handler/json/json_test.go","line":\d+,"function":".*json_test.TestCaller"\}`, buf.String())
+}
+
+type file struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *file) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	f := &file{}
+	h := json.New(f)
+	assert.NoError(t, h.Close(context.Background()))
+	assert.True(t, f.closed)
+
+	assert.NoError(t, json.New(os.Stderr).Close(context.Background()))
+	_, err := os.Stderr.Stat()
+	assert.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package kinesis
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 
@@ -50,3 +51,20 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	key := base64.StdEncoding.EncodeToString(uuid[:])
 	return h.producer.Put(b, key)
 }
+
+// Close implements log.Closer, stopping the producer after it has sent the
+// buffered records.
+func (h *Handler) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.producer.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
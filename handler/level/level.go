@@ -1,7 +1,11 @@
 // Package level implements a level filter handler.
 package level
 
-import "github.com/socifi/golog"
+import (
+	"context"
+
+	"github.com/socifi/golog"
+)
 
 // Handler implementation.
 type Handler struct {
@@ -25,3 +29,13 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 
 	return h.Handler.HandleLog(e)
 }
+
+// Flush implements log.Flusher.
+func (h *Handler) Flush(ctx context.Context) error {
+	return log.FlushHandler(ctx, h.Handler)
+}
+
+// Close implements log.Closer.
+func (h *Handler) Close(ctx context.Context) error {
+	return log.CloseHandler(ctx, h.Handler)
+}
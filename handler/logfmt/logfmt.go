@@ -2,6 +2,7 @@
 package logfmt
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
@@ -17,12 +18,14 @@ var Default = New(os.Stderr)
 type Handler struct {
 	mu  sync.Mutex
 	enc *logfmt.Encoder
+	w   io.Writer
 }
 
 // New handler.
 func New(w io.Writer) *Handler {
 	return &Handler{
 		enc: logfmt.NewEncoder(w),
+		w:   w,
 	}
 }
 
@@ -37,6 +40,10 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	h.enc.EncodeKeyval("level", e.Level)
 	h.enc.EncodeKeyval("message", e.Message)
 
+	if e.Caller != nil {
+		h.enc.EncodeKeyval("caller", e.Caller)
+	}
+
 	for _, name := range names {
 		h.enc.EncodeKeyval(name, e.Fields.Get(name))
 	}
@@ -45,3 +52,17 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 
 	return nil
 }
+
+// Flush implements log.Flusher, see log.FlushWriter.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.FlushWriter(h.w)
+}
+
+// Close implements log.Closer, see log.CloseWriter.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.CloseWriter(h.w)
+}
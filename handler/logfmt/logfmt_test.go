@@ -2,7 +2,9 @@ package logfmt_test
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
+	"runtime"
 	"testing"
 	"time"
 
@@ -42,3 +44,19 @@ func Benchmark(b *testing.B) {
 		ctx.Info("hello")
 	}
 }
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler:      logfmt.New(&buf),
+		Level:        log.InfoLevel,
+		ReportCaller: true,
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("hello")
+
+	expected := fmt.Sprintf("timestamp=1970-01-01T00:00:00Z level=200 message=hello caller=logfmt_test.go:%d\n", line+1)
+	assert.Equal(t, expected, buf.String())
+}
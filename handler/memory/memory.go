@@ -26,3 +26,15 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	h.Entries = append(h.Entries, e)
 	return nil
 }
+
+// Messages returns the messages of the entries handled so far.
+func (h *Handler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var v []string
+	for _, e := range h.Entries {
+		v = append(v, e.Message)
+	}
+	return v
+}
@@ -2,14 +2,41 @@
 package multi
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"github.com/socifi/golog"
 )
 
+// ErrTimeout is returned for handlers which did not finish within Timeout, or
+// which still have MaxPending timed out calls in flight.
+var ErrTimeout = errors.New("multi: handler timed out")
+
 // Handler implementation.
 type Handler struct {
 	Handlers []log.Handler
+	Parallel bool          // Parallel invokes the handlers concurrently
+	Timeout  time.Duration // Timeout limits waiting for each handler when Parallel (default: no limit)
+
+	// MaxPending limits the calls per handler still running after timing out
+	// when Parallel, so a hanging handler does not pile up goroutines (default: 100).
+	MaxPending int
+
+	mu      sync.Mutex
+	pending []*atomic.Int32
 }
 
+// States of a call when Parallel.
+const (
+	running int32 = iota
+	finished
+	abandoned
+)
+
 // New handler.
 func New(h ...log.Handler) *Handler {
 	return &Handler{
@@ -17,16 +44,123 @@ func New(h ...log.Handler) *Handler {
 	}
 }
 
-// HandleLog implements log.Handler.
+// HandleLog implements log.Handler. Every handler is invoked even when some
+// of them fail, so one broken sink does not stop entries reaching the others.
+// The errors are joined, each one prefixed with the identity of its handler.
 func (h *Handler) HandleLog(e *log.Entry) error {
-	for _, handler := range h.Handlers {
-		// TODO(tj): maybe just write to stderr here, definitely not ideal
-		// to miss out logging to a more critical handler if something
-		// goes wrong
-		if err := handler.HandleLog(e); err != nil {
-			return err
+	if h.Parallel {
+		return h.parallel(e)
+	}
+
+	var errs []error
+	for i, handler := range h.Handlers {
+		errs = append(errs, wrap(i, handler, handler.HandleLog(e)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// parallel invokes all handlers concurrently, waiting at most Timeout. Handlers
+// which time out keep running in the background. While MaxPending timed out
+// calls of a handler are running, further entries are not passed to it and
+// count as timed out.
+func (h *Handler) parallel(e *log.Entry) error {
+	type result struct {
+		i   int
+		err error
+	}
+
+	max := int32(h.MaxPending)
+	if max <= 0 {
+		max = 100
+	}
+
+	results := make(chan result, len(h.Handlers))
+	states := make([]*atomic.Int32, len(h.Handlers))
+	for i, handler := range h.Handlers {
+		pending := h.pendingOf(i)
+		if pending.Load() >= max {
+			results <- result{i, ErrTimeout}
+			continue
+		}
+
+		state := new(atomic.Int32)
+		states[i] = state
+		go func(i int, handler log.Handler) {
+			err := handler.HandleLog(e)
+			if !state.CompareAndSwap(running, finished) {
+				pending.Add(-1)
+			}
+			results <- result{i, err}
+		}(i, handler)
+	}
+
+	var timeout <-chan time.Time
+	if h.Timeout > 0 {
+		timer := time.NewTimer(h.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	errs := make([]error, len(h.Handlers))
+	done := make([]bool, len(h.Handlers))
+
+	for n := 0; n < len(h.Handlers); n++ {
+		select {
+		case r := <-results:
+			errs[r.i] = wrap(r.i, h.Handlers[r.i], r.err)
+			done[r.i] = true
+		case <-timeout:
+			for i, ok := range done {
+				if ok {
+					continue
+				}
+				if states[i] != nil && states[i].CompareAndSwap(running, abandoned) {
+					h.pendingOf(i).Add(1)
+				}
+				errs[i] = wrap(i, h.Handlers[i], ErrTimeout)
+			}
+			return errors.Join(errs...)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// pendingOf returns the number of timed out calls of handler `i` which are
+// still running.
+func (h *Handler) pendingOf(i int) *atomic.Int32 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for len(h.pending) <= i {
+		h.pending = append(h.pending, new(atomic.Int32))
+	}
+	return h.pending[i]
+}
+
+// Flush implements log.Flusher, flushing all handlers.
+func (h *Handler) Flush(ctx context.Context) error {
+	var errs []error
+	for i, handler := range h.Handlers {
+		errs = append(errs, wrap(i, handler, log.FlushHandler(ctx, handler)))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements log.Closer, closing all handlers.
+func (h *Handler) Close(ctx context.Context) error {
+	var errs []error
+	for i, handler := range h.Handlers {
+		errs = append(errs, wrap(i, handler, log.CloseHandler(ctx, handler)))
+	}
+	return errors.Join(errs...)
+}
+
+// wrap prefixes `err` with the position and type of handler `h`.
+func wrap(i int, h log.Handler, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("handler %d (%T): %w", i, h, err)
 }
@@ -1,6 +1,9 @@
 package multi_test
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,3 +32,90 @@ func Test(t *testing.T) {
 	assert.Len(t, a.Entries, 3)
 	assert.Len(t, b.Entries, 3)
 }
+
+func failing(err error) log.Handler {
+	return log.HandlerFunc(func(e *log.Entry) error {
+		return err
+	})
+}
+
+func TestErrors(t *testing.T) {
+	a := memory.New()
+	b := memory.New()
+	boom := errors.New("boom")
+
+	h := multi.New(failing(boom), a, failing(errors.New("down")), b)
+	err := h.HandleLog(&log.Entry{Message: "hello"})
+
+	assert.Len(t, a.Entries, 1)
+	assert.Len(t, b.Entries, 1)
+	assert.True(t, errors.Is(err, boom))
+	assert.EqualError(t, err, "handler 0 (log.HandlerFunc): boom\nhandler 2 (log.HandlerFunc): down")
+}
+
+func TestParallel(t *testing.T) {
+	a := memory.New()
+	block := make(chan struct{})
+	defer close(block)
+
+	h := multi.New(a, log.HandlerFunc(func(e *log.Entry) error {
+		<-block
+		return nil
+	}), failing(errors.New("boom")))
+	h.Parallel = true
+	h.Timeout = 20 * time.Millisecond
+
+	err := h.HandleLog(&log.Entry{Message: "hello"})
+
+	assert.Len(t, a.Entries, 1)
+	assert.True(t, errors.Is(err, multi.ErrTimeout))
+	assert.EqualError(t, err, "handler 1 (log.HandlerFunc): multi: handler timed out\nhandler 2 (log.HandlerFunc): boom")
+
+	h.Handlers = h.Handlers[:1]
+	assert.NoError(t, h.HandleLog(&log.Entry{Message: "world"}))
+	assert.Len(t, a.Entries, 2)
+}
+
+func TestParallel_maxPending(t *testing.T) {
+	var calls atomic.Int32
+	block := make(chan struct{})
+	defer close(block)
+
+	h := multi.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls.Add(1)
+		<-block
+		return nil
+	}))
+	h.Parallel = true
+	h.Timeout = time.Millisecond
+	h.MaxPending = 2
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, errors.Is(h.HandleLog(&log.Entry{}), multi.ErrTimeout))
+	}
+
+	assert.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestParallel_noTimeout(t *testing.T) {
+	var calls atomic.Int32
+
+	h := multi.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}))
+	h.Parallel = true
+
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, h.HandleLog(&log.Entry{}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(300), calls.Load())
+}
@@ -3,6 +3,7 @@ package papertrail
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log/syslog"
 	"net"
@@ -59,6 +60,10 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 	enc.EncodeKeyval("level", e.Level)
 	enc.EncodeKeyval("message", e.Message)
 
+	if e.Caller != nil {
+		enc.EncodeKeyval("caller", e.Caller)
+	}
+
 	for k, v := range e.Fields {
 		enc.EncodeKeyval(k, v)
 	}
@@ -73,3 +78,10 @@ func (h *Handler) HandleLog(e *log.Entry) error {
 
 	return err
 }
+
+// Close implements log.Closer, closing the connection.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.conn.Close()
+}
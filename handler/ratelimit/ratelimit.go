@@ -0,0 +1,284 @@
+// Package ratelimit implements a handler which caps the entries and bytes per
+// second passed on with token buckets. Dropped entries are reported by
+// summary entries such as "suppressed 1234 messages like X in the last 10s",
+// at most MaxSummaries per Interval plus one for all other suppressed entries.
+// There is no timer, the summaries of an Interval are emitted with the first
+// entry after it, or on Flush and Close.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// ByLevel keys entries by level, so every level has its own bucket.
+func ByLevel(e *log.Entry) string {
+	return strconv.Itoa(e.Level)
+}
+
+// ByMessage keys entries by level and message.
+func ByMessage(e *log.Entry) string {
+	return strconv.Itoa(e.Level) + "\x00" + e.Message
+}
+
+// Size returns the size of `e` encoded as JSON.
+func Size(e *log.Entry) int {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return len(e.Message)
+	}
+	return len(b)
+}
+
+// Config for handler.
+type Config struct {
+	Rate           float64                 // Rate of entries per second per key (default: 10)
+	Burst          int                     // Burst of entries per key passed at once (default: Rate)
+	BytesPerSecond int                     // BytesPerSecond of all entries together, larger entries are always dropped (default: no limit)
+	Key            func(*log.Entry) string // Key of the bucket of an entry (default: ByLevel)
+	Size           func(*log.Entry) int    // Size of an entry in bytes (default: Size)
+	Interval       time.Duration           // Interval of the summary entries (default: 10s)
+	MaxSummaries   int                     // MaxSummaries per Interval, further keys share one summary (default: 10)
+	Now            func() time.Time        // Now returns the current time (default: time.Now)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Rate == 0 {
+		c.Rate = 10
+	}
+
+	if c.Burst == 0 {
+		c.Burst = int(c.Rate)
+		if c.Burst < 1 {
+			c.Burst = 1
+		}
+	}
+
+	if c.Key == nil {
+		c.Key = ByLevel
+	}
+
+	if c.Size == nil {
+		c.Size = Size
+	}
+
+	if c.Interval == 0 {
+		c.Interval = 10 * time.Second
+	}
+
+	if c.MaxSummaries == 0 {
+		c.MaxSummaries = 10
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// bucket of tokens.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+}
+
+// suppressed entries of a key.
+type suppressed struct {
+	entry *log.Entry
+	count int
+	bytes int
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	bytes      *bucket
+	suppressed map[string]*suppressed
+	order      []string
+	other      *suppressed
+	since      time.Time
+}
+
+// New handler limiting entries passed to `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	now := config.Now()
+	return &Handler{
+		Config:     config,
+		Handler:    h,
+		buckets:    make(map[string]*bucket),
+		bytes:      &bucket{tokens: float64(config.BytesPerSecond), last: now},
+		suppressed: make(map[string]*suppressed),
+		since:      now,
+	}
+}
+
+// HandleLog implements log.Handler. Summaries of the entries suppressed in
+// the last Interval are emitted before the next entry, or on Flush.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	now := h.Now()
+	size := 0
+	if h.BytesPerSecond > 0 {
+		size = h.Size(e)
+	}
+
+	h.mu.Lock()
+	var summaries []*log.Entry
+	if now.Sub(h.since) >= h.Interval {
+		summaries = h.summaries(now)
+	}
+	allow := h.allow(e, now, size)
+	h.mu.Unlock()
+
+	err := h.emit(summaries)
+	if allow {
+		err = errors.Join(err, h.Handler.HandleLog(e))
+	}
+	return err
+}
+
+// Flush implements log.Flusher, it emits pending summaries and flushes the handler.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	summaries := h.summaries(h.Now())
+	h.mu.Unlock()
+
+	return errors.Join(h.emit(summaries), log.FlushHandler(ctx, h.Handler))
+}
+
+// Close implements log.Closer, it emits pending summaries and closes the handler.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	summaries := h.summaries(h.Now())
+	h.mu.Unlock()
+
+	return errors.Join(h.emit(summaries), log.CloseHandler(ctx, h.Handler))
+}
+
+// allow returns whether `e` passes, or records it as suppressed.
+func (h *Handler) allow(e *log.Entry, now time.Time, size int) bool {
+	key := h.Key(e)
+
+	b, ok := h.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(h.Burst), last: now}
+		h.buckets[key] = b
+	}
+	b.refill(now, h.Rate, float64(h.Burst))
+
+	if h.BytesPerSecond > 0 {
+		h.bytes.refill(now, float64(h.BytesPerSecond), float64(h.BytesPerSecond))
+	}
+
+	if b.tokens >= 1 && (h.BytesPerSecond <= 0 || h.bytes.tokens >= float64(size)) {
+		b.tokens--
+		h.bytes.tokens -= float64(size)
+		return true
+	}
+
+	s, ok := h.suppressed[key]
+	switch {
+	case ok:
+	case len(h.order) < h.MaxSummaries:
+		s = &suppressed{entry: e}
+		h.suppressed[key] = s
+		h.order = append(h.order, key)
+	default:
+		// the summary of other keys takes the most severe entry
+		if h.other == nil {
+			h.other = &suppressed{entry: e}
+		} else if e.Level > h.other.entry.Level {
+			h.other.entry = e
+		}
+		s = h.other
+	}
+	s.count++
+	s.bytes += size
+	return false
+}
+
+// summaries returns the summary entries of the suppressed entries and starts
+// a new interval, caller must hold mu.
+func (h *Handler) summaries(now time.Time) []*log.Entry {
+	var entries []*log.Entry
+	elapsed := now.Sub(h.since).Round(time.Second)
+
+	for _, key := range h.order {
+		s := h.suppressed[key]
+		msg := fmt.Sprintf("suppressed %d messages like %q in the last %s", s.count, s.entry.Message, elapsed)
+		entries = append(entries, h.summary(s, msg, now))
+	}
+
+	if h.other != nil {
+		msg := fmt.Sprintf("suppressed %d other messages in the last %s", h.other.count, elapsed)
+		entries = append(entries, h.summary(h.other, msg, now))
+	}
+
+	// forget full buckets, they behave the same as new ones
+	for key, b := range h.buckets {
+		b.refill(now, h.Rate, float64(h.Burst))
+		if b.tokens >= float64(h.Burst) {
+			delete(h.buckets, key)
+		}
+	}
+
+	h.suppressed = make(map[string]*suppressed)
+	h.order = nil
+	h.other = nil
+	h.since = now
+	return entries
+}
+
+// summary returns the summary entry `msg` of `s`.
+func (h *Handler) summary(s *suppressed, msg string, now time.Time) *log.Entry {
+	fields := log.Fields{
+		"suppressed": s.count,
+	}
+	if h.BytesPerSecond > 0 {
+		fields["suppressed_bytes"] = s.bytes
+	}
+
+	return &log.Entry{
+		Logger:    s.entry.Logger,
+		Fields:    fields,
+		Level:     s.entry.Level,
+		LevelName: s.entry.LevelName,
+		Timestamp: now,
+		Message:   msg,
+		Env:       s.entry.Env,
+		Project:   s.entry.Project,
+		Hostname:  s.entry.Hostname,
+		Name:      s.entry.Name,
+	}
+}
+
+// emit passes the summary entries to the handler.
+func (h *Handler) emit(entries []*log.Entry) error {
+	var errs []error
+	for _, e := range entries {
+		if err := h.Handler.HandleLog(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
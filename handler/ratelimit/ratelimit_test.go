@@ -0,0 +1,122 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/ratelimit"
+)
+
+func Test(t *testing.T) {
+	now := time.Unix(0, 0)
+	h := memory.New()
+
+	r := ratelimit.New(h, &ratelimit.Config{
+		Rate:  1,
+		Burst: 2,
+		Key:   ratelimit.ByMessage,
+		Now:   func() time.Time { return now },
+	})
+
+	l := &log.Logger{
+		Handler: r,
+		Level:   log.InfoLevel,
+	}
+
+	for i := 0; i < 5; i++ {
+		l.Info("hot")
+	}
+	l.Warn("hot")
+
+	now = now.Add(time.Second)
+	l.Info("hot")
+	l.Info("hot")
+
+	now = now.Add(9 * time.Second)
+	l.Info("cold")
+
+	assert.Equal(t, []string{
+		"hot", "hot",
+		"hot",
+		"hot",
+		`suppressed 4 messages like "hot" in the last 10s`,
+		"cold",
+	}, h.Messages())
+
+	summary := h.Entries[4]
+	assert.Equal(t, int(log.InfoLevel), summary.Level)
+	assert.Equal(t, log.Fields{"suppressed": 4}, summary.Fields)
+}
+
+func TestBytes(t *testing.T) {
+	now := time.Unix(0, 0)
+	h := memory.New()
+
+	r := ratelimit.New(h, &ratelimit.Config{
+		Rate:           100,
+		BytesPerSecond: 10,
+		Size:           func(e *log.Entry) int { return len(e.Message) },
+		Now:            func() time.Time { return now },
+	})
+
+	l := &log.Logger{
+		Handler: r,
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("12345678")
+	l.Info("12345678")
+	l.Info("1234")
+
+	now = now.Add(time.Second)
+	l.Info("1234")
+
+	assert.NoError(t, r.Flush(context.Background()))
+
+	assert.Equal(t, []string{
+		"12345678",
+		"1234",
+		`suppressed 2 messages like "12345678" in the last 1s`,
+	}, h.Messages())
+	assert.Equal(t, log.Fields{"suppressed": 2, "suppressed_bytes": 12}, h.Entries[2].Fields)
+}
+
+func TestMaxSummaries(t *testing.T) {
+	h := memory.New()
+
+	r := ratelimit.New(h, &ratelimit.Config{
+		Rate:         1,
+		Key:          ratelimit.ByMessage,
+		MaxSummaries: 2,
+		Now:          func() time.Time { return time.Unix(0, 0) },
+	})
+
+	l := &log.Logger{
+		Handler: r,
+		Level:   log.InfoLevel,
+	}
+
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		l.Info(msg)
+		l.Info(msg)
+	}
+	l.Warn("e")
+	l.Warn("e")
+
+	assert.NoError(t, r.Flush(context.Background()))
+	assert.Equal(t, []string{
+		"a", "b", "c", "d", "e",
+		`suppressed 1 messages like "a" in the last 0s`,
+		`suppressed 1 messages like "b" in the last 0s`,
+		"suppressed 3 other messages in the last 0s",
+	}, h.Messages())
+
+	summary := h.Entries[7]
+	assert.Equal(t, int(log.WarnLevel), summary.Level)
+	assert.Equal(t, log.Fields{"suppressed": 3}, summary.Fields)
+}
@@ -0,0 +1,154 @@
+// Package retry implements a handler which retries failed entries with
+// exponential backoff. The backoff blocks the goroutine logging, so remote
+// handlers are best wrapped in async first.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// permanent marks errors which are not retried.
+type permanent struct {
+	error
+}
+
+// Unwrap returns the wrapped error.
+func (p permanent) Unwrap() error {
+	return p.error
+}
+
+// Permanent wraps `err` so that it is not retried by the default Retryable.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanent{err}
+}
+
+// IsRetryable is the default Retryable, it retries all errors except the
+// ones wrapped by Permanent.
+func IsRetryable(err error) bool {
+	var p permanent
+	return !errors.As(err, &p)
+}
+
+// Config for handler.
+type Config struct {
+	MaxAttempts    int           // MaxAttempts including the first one (default: 3)
+	InitialBackoff time.Duration // InitialBackoff before the second attempt (default: 100ms)
+	MaxBackoff     time.Duration // MaxBackoff between attempts (default: 10s)
+	Multiplier     float64       // Multiplier of the backoff after each attempt (default: 2)
+	Jitter         float64       // Jitter randomizes the backoff by ± the fraction (default: 0.2, negative disables it)
+
+	Retryable func(error) bool        // Retryable classifies errors (default: IsRetryable)
+	OnGiveUp  func(*log.Entry, error) // OnGiveUp is called with entries which were not handled
+
+	Sleep  func(time.Duration) // Sleep waits between attempts (default: time.Sleep)
+	Random func() float64      // Random returns a number in [0, 1) for jitter (default: rand.Float64)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 3
+	}
+
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = 10 * time.Second
+	}
+
+	if c.Multiplier == 0 {
+		c.Multiplier = 2
+	}
+
+	if c.Jitter == 0 {
+		c.Jitter = 0.2
+	}
+
+	if c.Retryable == nil {
+		c.Retryable = IsRetryable
+	}
+
+	if c.Sleep == nil {
+		c.Sleep = time.Sleep
+	}
+
+	if c.Random == nil {
+		c.Random = rand.Float64
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+}
+
+// New handler retrying `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:  config,
+		Handler: h,
+	}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	var err error
+
+	attempt := 1
+	for ; ; attempt++ {
+		err = h.Handler.HandleLog(e)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= h.MaxAttempts || !h.Retryable(err) {
+			break
+		}
+
+		h.Sleep(h.Backoff(attempt))
+	}
+
+	if h.OnGiveUp != nil {
+		h.OnGiveUp(e, err)
+	}
+
+	return fmt.Errorf("retry: giving up after %d attempts: %w", attempt, err)
+}
+
+// Backoff returns the time to wait after failed attempt number `attempt`.
+func (h *Handler) Backoff(attempt int) time.Duration {
+	d := float64(h.InitialBackoff) * math.Pow(h.Multiplier, float64(attempt-1))
+	if d > float64(h.MaxBackoff) {
+		d = float64(h.MaxBackoff)
+	}
+
+	if h.Jitter > 0 {
+		d *= 1 + h.Jitter*(2*h.Random()-1)
+	}
+
+	return time.Duration(d)
+}
+
+// Flush implements log.Flusher.
+func (h *Handler) Flush(ctx context.Context) error {
+	return log.FlushHandler(ctx, h.Handler)
+}
+
+// Close implements log.Closer.
+func (h *Handler) Close(ctx context.Context) error {
+	return log.CloseHandler(ctx, h.Handler)
+}
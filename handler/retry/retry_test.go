@@ -0,0 +1,83 @@
+package retry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/retry"
+)
+
+func Test(t *testing.T) {
+	var slept []time.Duration
+	var calls int
+
+	h := retry.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls++
+		if calls <= 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	}), &retry.Config{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+		Jitter:         -1,
+		Sleep:          func(d time.Duration) { slept = append(slept, d) },
+	})
+
+	assert.NoError(t, h.HandleLog(&log.Entry{Message: "hello"}))
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, slept)
+}
+
+func TestGiveUp(t *testing.T) {
+	var gaveUp []string
+	var calls int
+	timeout := errors.New("timeout")
+
+	h := retry.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls++
+		return timeout
+	}), &retry.Config{
+		Sleep:    func(time.Duration) {},
+		OnGiveUp: func(e *log.Entry, err error) { gaveUp = append(gaveUp, e.Message) },
+	})
+
+	err := h.HandleLog(&log.Entry{Message: "hello"})
+	assert.True(t, errors.Is(err, timeout))
+	assert.EqualError(t, err, "retry: giving up after 3 attempts: timeout")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []string{"hello"}, gaveUp)
+}
+
+func TestPermanent(t *testing.T) {
+	var calls int
+
+	h := retry.New(log.HandlerFunc(func(e *log.Entry) error {
+		calls++
+		return retry.Permanent(errors.New("bad request"))
+	}), &retry.Config{
+		Sleep: func(time.Duration) { t.Fatal("permanent errors must not be retried") },
+	})
+
+	assert.EqualError(t, h.HandleLog(&log.Entry{}), "retry: giving up after 1 attempts: bad request")
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff(t *testing.T) {
+	random := 0.0
+	h := retry.New(memory.New(), &retry.Config{
+		Random: func() float64 { return random },
+	})
+
+	assert.Equal(t, 80*time.Millisecond, h.Backoff(1))
+	random = 0.5
+	assert.Equal(t, 200*time.Millisecond, h.Backoff(2))
+	random = 0.99
+	assert.Equal(t, 11960*time.Millisecond, h.Backoff(10))
+}
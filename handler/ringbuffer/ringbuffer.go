@@ -0,0 +1,179 @@
+// Package ringbuffer implements a "debug on error" handler. It keeps the last
+// entries below a level in memory per key, e.g. per request, and only passes
+// them on when an error is logged for the same key.
+package ringbuffer
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/socifi/golog"
+)
+
+// Field returns a Key function keying entries by the value of field `name`.
+func Field(name string) func(*log.Entry) string {
+	return func(e *log.Entry) string {
+		v, ok := e.Fields[name]
+		if !ok {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// Config for handler.
+type Config struct {
+	Size    int                     // Size of the buffer per key (default: 100)
+	Level   log.Level               // Level below which entries are buffered (default: ErrorLevel)
+	Trigger log.Level               // Trigger level emitting the buffer of the key (default: ErrorLevel)
+	Key     func(*log.Entry) string // Key of the buffer of an entry (default: Field("request_id"))
+	MaxKeys int                     // MaxKeys buffered, the least recently used are evicted (default: 1000)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Size == 0 {
+		c.Size = 100
+	}
+
+	if c.Level == 0 {
+		c.Level = log.ErrorLevel
+	}
+
+	if c.Trigger == 0 {
+		c.Trigger = log.ErrorLevel
+	}
+
+	if c.Key == nil {
+		c.Key = Field("request_id")
+	}
+
+	if c.MaxKeys == 0 {
+		c.MaxKeys = 1000
+	}
+}
+
+// ring of the last entries of a key.
+type ring struct {
+	key     string
+	entries []*log.Entry
+	next    int
+}
+
+// add appends `e`, overwriting the oldest entry when the ring is full.
+func (r *ring) add(e *log.Entry, size int) {
+	if len(r.entries) < size {
+		r.entries = append(r.entries, e)
+		return
+	}
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % size
+}
+
+// ordered returns the entries from the oldest one.
+func (r *ring) ordered() []*log.Entry {
+	return append(r.entries[r.next:len(r.entries):len(r.entries)], r.entries[:r.next]...)
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	mu    sync.Mutex
+	rings map[string]*list.Element
+	lru   *list.List
+}
+
+// New handler buffering entries passed to `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:  config,
+		Handler: h,
+		rings:   make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// HandleLog implements log.Handler. Entries below Level are buffered, the
+// rest is passed on. Entries at or above Trigger are preceded by the buffered
+// entries of their key, in the order they were logged. Entries without a key
+// are passed on right away, as they cannot be told apart.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	key := h.Key(e)
+	if key == "" {
+		return h.Handler.HandleLog(e)
+	}
+
+	h.mu.Lock()
+	if e.Level < h.Level.Int() {
+		h.buffer(key, e)
+		h.mu.Unlock()
+		return nil
+	}
+
+	var buffered []*log.Entry
+	if e.Level >= h.Trigger.Int() {
+		buffered = h.take(key)
+	}
+	h.mu.Unlock()
+
+	var errs []error
+	for _, b := range append(buffered, e) {
+		if err := h.Handler.HandleLog(b); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Discard drops the buffered entries of `key`, e.g. at the end of a request.
+func (h *Handler) Discard(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.take(key)
+}
+
+// Flush implements log.Flusher. Buffered entries are not flushed.
+func (h *Handler) Flush(ctx context.Context) error {
+	return log.FlushHandler(ctx, h.Handler)
+}
+
+// Close implements log.Closer.
+func (h *Handler) Close(ctx context.Context) error {
+	return log.CloseHandler(ctx, h.Handler)
+}
+
+// buffer adds `e` to the ring of `key`, caller must hold mu.
+func (h *Handler) buffer(key string, e *log.Entry) {
+	el, ok := h.rings[key]
+	if ok {
+		h.lru.MoveToFront(el)
+	} else {
+		if h.lru.Len() >= h.MaxKeys {
+			oldest := h.lru.Back()
+			h.lru.Remove(oldest)
+			delete(h.rings, oldest.Value.(*ring).key)
+		}
+		el = h.lru.PushFront(&ring{key: key})
+		h.rings[key] = el
+	}
+
+	el.Value.(*ring).add(e, h.Size)
+}
+
+// take removes the ring of `key` and returns its entries, caller must hold mu.
+func (h *Handler) take(key string) []*log.Entry {
+	el, ok := h.rings[key]
+	if !ok {
+		return nil
+	}
+
+	h.lru.Remove(el)
+	delete(h.rings, key)
+	return el.Value.(*ring).ordered()
+}
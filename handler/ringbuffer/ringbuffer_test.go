@@ -0,0 +1,73 @@
+package ringbuffer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/ringbuffer"
+)
+
+func Test(t *testing.T) {
+	h := memory.New()
+
+	r := ringbuffer.New(h, &ringbuffer.Config{
+		Size:  3,
+		Level: log.InfoLevel,
+	})
+
+	l := &log.Logger{
+		Handler: r,
+		Level:   log.DebugLevel,
+	}
+
+	a := l.WithField("request_id", "a")
+	b := l.WithField("request_id", "b")
+
+	for _, msg := range []string{"a1", "a2", "a3", "a4"} {
+		a.Debug(msg)
+	}
+	b.Debug("b1")
+	a.Info("a5")
+	assert.Equal(t, []string{"a5"}, h.Messages())
+
+	a.Error("a6")
+	assert.Equal(t, []string{"a5", "a2", "a3", "a4", "a6"}, h.Messages())
+
+	a.Debug("a7")
+	b.Error("b2")
+	a.Error("a8")
+	assert.Equal(t, []string{"a5", "a2", "a3", "a4", "a6", "b1", "b2", "a7", "a8"}, h.Messages())
+
+	l.Debug("startup")
+	assert.Equal(t, []string{"a5", "a2", "a3", "a4", "a6", "b1", "b2", "a7", "a8", "startup"}, h.Messages())
+}
+
+func TestMaxKeys(t *testing.T) {
+	h := memory.New()
+
+	r := ringbuffer.New(h, &ringbuffer.Config{
+		MaxKeys: 2,
+	})
+
+	l := &log.Logger{
+		Handler: r,
+		Level:   log.DebugLevel,
+	}
+
+	l.WithField("request_id", "a").Info("a1")
+	l.WithField("request_id", "b").Info("b1")
+	l.WithField("request_id", "a").Info("a2")
+	l.WithField("request_id", "c").Info("c1")
+
+	l.WithField("request_id", "b").Error("b2")
+	l.WithField("request_id", "a").Error("a3")
+	assert.Equal(t, []string{"b2", "a1", "a2", "a3"}, h.Messages())
+
+	l.WithField("request_id", "c").Info("c2")
+	r.Discard("c")
+	l.WithField("request_id", "c").Error("c3")
+	assert.Equal(t, []string{"b2", "a1", "a2", "a3", "c3"}, h.Messages())
+}
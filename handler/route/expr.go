@@ -0,0 +1,342 @@
+package route
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/socifi/golog"
+)
+
+// Parse returns the predicate of expression `expr`. Conditions compare the
+// level, message, env, project or a field of an entry and can be combined
+// with and, or, not and parentheses:
+//
+//	level >= error and not message ~ "^health"
+//	fields.audit or (env == prod and fields.status == 500)
+//
+// Levels compare with ==, !=, <, <=, > and >=, the rest with == and !=,
+// messages also match regular expressions with ~. A field without comparison
+// matches entries with the field set. Values may be quoted.
+func Parse(expr string) (Predicate, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	pred, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t, ok := p.peek(); ok {
+		return nil, fmt.Errorf("route: unexpected %q in %q", t.text, expr)
+	}
+
+	return pred, nil
+}
+
+// MustParse is like Parse but panics if the expression is invalid.
+func MustParse(expr string) Predicate {
+	p, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// token of an expression, values are quoted strings.
+type token struct {
+	text  string
+	value bool
+}
+
+// tokenize splits `expr` into tokens.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '~':
+			tokens = append(tokens, token{text: expr[i : i+1]})
+			i++
+		case strings.ContainsRune("=!<>&|", rune(c)):
+			n := 1
+			if i+1 < len(expr) && strings.ContainsRune("=&|", rune(expr[i+1])) {
+				n = 2
+			}
+			tokens = append(tokens, token{text: expr[i : i+n]})
+			i += n
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("route: unterminated string in %q", expr)
+			}
+			s := expr[i+1 : i+1+end]
+			if c == '"' {
+				var err error
+				if s, err = strconv.Unquote(expr[i : i+2+end]); err != nil {
+					return nil, fmt.Errorf("route: invalid string %s in %q", expr[i:i+2+end], expr)
+				}
+			}
+			tokens = append(tokens, token{text: s, value: true})
+			i += end + 2
+		default:
+			j := i
+			for j < len(expr) && isWord(rune(expr[j])) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("route: unexpected %q in %q", c, expr)
+			}
+			tokens = append(tokens, token{text: expr[i:j]})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// isWord returns whether `r` may be part of a bare word.
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:/", r)
+}
+
+// parser of a tokenized expression.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the next token.
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// next returns and consumes the next token.
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("route: unexpected end of expression")
+	}
+	p.pos++
+	return t, nil
+}
+
+// keyword consumes the next token if it is one of `keywords`.
+func (p *parser) keyword(keywords ...string) bool {
+	t, ok := p.peek()
+	if !ok || t.value {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.text, k) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// or parses: and { ("or" | "||") and }.
+func (p *parser) or() (Predicate, error) {
+	pred, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []Predicate{pred}
+	for p.keyword("or", "||") {
+		pred, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+	return Or(preds...), nil
+}
+
+// and parses: unary { ("and" | "&&") unary }.
+func (p *parser) and() (Predicate, error) {
+	pred, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []Predicate{pred}
+	for p.keyword("and", "&&") {
+		pred, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+	return And(preds...), nil
+}
+
+// unary parses: ("not" | "!") unary | "(" or ")" | condition.
+func (p *parser) unary() (Predicate, error) {
+	if p.keyword("not", "!") {
+		pred, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(pred), nil
+	}
+
+	if p.keyword("(") {
+		pred, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("route: missing closing parenthesis")
+		}
+		return pred, nil
+	}
+
+	return p.condition()
+}
+
+// condition parses a comparison of an entry attribute with a value.
+func (p *parser) condition() (Predicate, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.value {
+		return nil, fmt.Errorf("route: unexpected string %q", t.text)
+	}
+
+	attr := strings.ToLower(t.text)
+	if strings.HasPrefix(attr, "fields.") {
+		name := t.text[len("fields."):]
+		if !p.operatorNext() {
+			return HasField(name), nil
+		}
+		op, value, err := p.comparison("==", "!=")
+		if err != nil {
+			return nil, err
+		}
+		return negate(op, FieldEquals(name, value)), nil
+	}
+
+	switch attr {
+	case "level":
+		op, value, err := p.comparison("==", "!=", "<", "<=", ">", ">=")
+		if err != nil {
+			return nil, err
+		}
+		level, err := log.ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("route: %w", err)
+		}
+		return levelPredicate(op, level), nil
+	case "message":
+		op, value, err := p.comparison("==", "!=", "~")
+		if err != nil {
+			return nil, err
+		}
+		if op == "~" {
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("route: %w", err)
+			}
+			return Message(re), nil
+		}
+		return negate(op, func(e *log.Entry) bool { return e.Message == value }), nil
+	case "env":
+		op, value, err := p.comparison("==", "!=")
+		if err != nil {
+			return nil, err
+		}
+		return negate(op, Env(value)), nil
+	case "project":
+		op, value, err := p.comparison("==", "!=")
+		if err != nil {
+			return nil, err
+		}
+		return negate(op, Project(value)), nil
+	default:
+		return nil, fmt.Errorf("route: unknown attribute %q", t.text)
+	}
+}
+
+// operatorNext returns whether the next token is a comparison operator.
+func (p *parser) operatorNext() bool {
+	t, ok := p.peek()
+	return ok && !t.value && strings.ContainsAny(t.text[:1], "=!<>~")
+}
+
+// comparison parses an operator, one of `ops`, followed by a value.
+func (p *parser) comparison(ops ...string) (string, string, error) {
+	op, err := p.next()
+	if err != nil {
+		return "", "", err
+	}
+
+	valid := false
+	for _, o := range ops {
+		if !op.value && op.text == o {
+			valid = true
+		}
+	}
+	if !valid {
+		return "", "", fmt.Errorf("route: unexpected %q, expected one of %s", op.text, strings.Join(ops, " "))
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return "", "", err
+	}
+	if !value.value && !isWord(rune(value.text[0])) {
+		return "", "", fmt.Errorf("route: unexpected %q, expected value", value.text)
+	}
+
+	return op.text, value.text, nil
+}
+
+// negate returns the negation of `pred` for the != operator.
+func negate(op string, pred Predicate) Predicate {
+	if op == "!=" {
+		return Not(pred)
+	}
+	return pred
+}
+
+// levelPredicate returns the predicate of the level comparison.
+func levelPredicate(op string, level log.Level) Predicate {
+	l := level.Int()
+	switch op {
+	case "<":
+		return LevelRange(math.MinInt, log.Level(l-1))
+	case "<=":
+		return LevelRange(math.MinInt, level)
+	case ">":
+		return LevelRange(log.Level(l+1), math.MaxInt)
+	case ">=":
+		return MinLevel(level)
+	case "!=":
+		return Not(LevelRange(level, level))
+	default:
+		return LevelRange(level, level)
+	}
+}
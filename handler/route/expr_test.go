@@ -0,0 +1,64 @@
+package route_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/route"
+)
+
+func TestParse(t *testing.T) {
+	e := &log.Entry{
+		Level:   int(log.ErrorLevel),
+		Message: "GET /health failed",
+		Fields:  log.Fields{"status": 500.0, "user": "tobi", "audit": true},
+		Env:     "prod",
+		Project: "api",
+	}
+
+	cases := map[string]bool{
+		`level >= error`:                                     true,
+		`level > error`:                                      false,
+		`level < warn`:                                       false,
+		`level <= 400`:                                       true,
+		`level == ERROR && level != warn`:                    true,
+		`message ~ "^GET /health"`:                           true,
+		`not message ~ '^GET'`:                               false,
+		`message == "GET /health failed"`:                    true,
+		`env == prod and project != web`:                     true,
+		`fields.audit`:                                       true,
+		`!fields.missing`:                                    true,
+		`fields.status == 500 and fields.user == "tobi"`:     true,
+		`fields.user != tobi`:                                false,
+		`env == dev or (level >= critical || fields.audit)`:  true,
+		`(env == dev or level >= critical) and fields.audit`: false,
+	}
+
+	for expr, want := range cases {
+		p, err := route.Parse(expr)
+		if assert.NoError(t, err, expr) {
+			assert.Equal(t, want, p(e), expr)
+		}
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`level >=`,
+		`level ~ error`,
+		`level >= nope`,
+		`message ~ "("`,
+		`env = prod`,
+		`host == x`,
+		`(fields.audit`,
+		`fields.audit)`,
+		`env == "prod`,
+		`fields.audit and`,
+	} {
+		_, err := route.Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,170 @@
+// Package route implements a handler which dispatches entries to handlers by
+// predicates over the entries, e.g. audit entries to a dedicated file and
+// everything else to Elasticsearch.
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+
+	"github.com/socifi/golog"
+)
+
+// Predicate reports whether an entry matches.
+type Predicate func(*log.Entry) bool
+
+// And matches entries matching all of `p`.
+func And(p ...Predicate) Predicate {
+	return func(e *log.Entry) bool {
+		for _, p := range p {
+			if !p(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches entries matching any of `p`.
+func Or(p ...Predicate) Predicate {
+	return func(e *log.Entry) bool {
+		for _, p := range p {
+			if p(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches entries not matching `p`.
+func Not(p Predicate) Predicate {
+	return func(e *log.Entry) bool {
+		return !p(e)
+	}
+}
+
+// LevelRange matches entries with level between `min` and `max` inclusive.
+func LevelRange(min, max log.Level) Predicate {
+	return func(e *log.Entry) bool {
+		return e.Level >= min.Int() && e.Level <= max.Int()
+	}
+}
+
+// MinLevel matches entries at or above `level`.
+func MinLevel(level log.Level) Predicate {
+	return LevelRange(level, math.MaxInt)
+}
+
+// Message matches entries with message matching `re`.
+func Message(re *regexp.Regexp) Predicate {
+	return func(e *log.Entry) bool {
+		return re.MatchString(e.Message)
+	}
+}
+
+// HasField matches entries with field `name` set.
+func HasField(name string) Predicate {
+	return func(e *log.Entry) bool {
+		_, ok := e.Fields[name]
+		return ok
+	}
+}
+
+// FieldEquals matches entries with field `name` equal to `value`. The values
+// are compared formatted with fmt.Sprint, so 500 equals "500".
+func FieldEquals(name string, value interface{}) Predicate {
+	s := fmt.Sprint(value)
+	return func(e *log.Entry) bool {
+		v, ok := e.Fields[name]
+		return ok && fmt.Sprint(v) == s
+	}
+}
+
+// Env matches entries of environment `env`.
+func Env(env string) Predicate {
+	return func(e *log.Entry) bool {
+		return e.Env == env
+	}
+}
+
+// Project matches entries of project `project`.
+func Project(project string) Predicate {
+	return func(e *log.Entry) bool {
+		return e.Project == project
+	}
+}
+
+// Route of the entries matching Predicate to Handler.
+type Route struct {
+	Predicate Predicate
+	Handler   log.Handler
+}
+
+// Handler implementation.
+type Handler struct {
+	Routes  []Route
+	Default log.Handler // Default handles entries matching no route (optional)
+}
+
+// New handler dispatching entries to the first matching route, or to `def`
+// which may be nil to drop the entries.
+func New(def log.Handler, routes ...Route) *Handler {
+	return &Handler{
+		Routes:  routes,
+		Default: def,
+	}
+}
+
+// Filter returns a handler passing only the entries matching `p` to `h`.
+func Filter(p Predicate, h log.Handler) *Handler {
+	return New(nil, Route{Predicate: p, Handler: h})
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	for _, r := range h.Routes {
+		if r.Predicate(e) {
+			return r.Handler.HandleLog(e)
+		}
+	}
+
+	if h.Default != nil {
+		return h.Default.HandleLog(e)
+	}
+
+	return nil
+}
+
+// Flush implements log.Flusher, flushing all handlers.
+func (h *Handler) Flush(ctx context.Context) error {
+	var errs []error
+	for _, handler := range h.handlers() {
+		errs = append(errs, log.FlushHandler(ctx, handler))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements log.Closer, closing all handlers.
+func (h *Handler) Close(ctx context.Context) error {
+	var errs []error
+	for _, handler := range h.handlers() {
+		errs = append(errs, log.CloseHandler(ctx, handler))
+	}
+	return errors.Join(errs...)
+}
+
+// handlers returns the handlers of all routes and the default handler.
+func (h *Handler) handlers() []log.Handler {
+	var handlers []log.Handler
+	for _, r := range h.Routes {
+		handlers = append(handlers, r.Handler)
+	}
+	if h.Default != nil {
+		handlers = append(handlers, h.Default)
+	}
+	return handlers
+}
@@ -0,0 +1,71 @@
+package route_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/route"
+)
+
+func Test(t *testing.T) {
+	audit := memory.New()
+	errs := memory.New()
+	rest := memory.New()
+
+	h := route.New(rest,
+		route.Route{Predicate: route.HasField("audit"), Handler: audit},
+		route.Route{Predicate: route.And(route.MinLevel(log.ErrorLevel), route.Not(route.Env("dev"))), Handler: errs},
+	)
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.DebugLevel,
+	}
+
+	l.WithField("audit", true).Error("login")
+	l.SetEnvProject("prod", "api").Error("prod error")
+	l.SetEnvProject("dev", "api").Error("dev error")
+	l.Info("hello")
+
+	assert.Equal(t, []string{"login"}, audit.Messages())
+	assert.Equal(t, []string{"prod error"}, errs.Messages())
+	assert.Equal(t, []string{"dev error", "hello"}, rest.Messages())
+}
+
+func TestPredicates(t *testing.T) {
+	e := &log.Entry{
+		Level:   int(log.WarnLevel),
+		Message: "GET /health",
+		Fields:  log.Fields{"status": 500.0, "user": "tobi"},
+		Env:     "prod",
+		Project: "api",
+	}
+
+	assert.True(t, route.LevelRange(log.InfoLevel, log.WarnLevel)(e))
+	assert.False(t, route.LevelRange(log.ErrorLevel, log.FatalLevel)(e))
+	assert.True(t, route.Message(regexp.MustCompile("^GET"))(e))
+	assert.True(t, route.FieldEquals("status", 500)(e))
+	assert.False(t, route.FieldEquals("user", "loki")(e))
+	assert.False(t, route.HasField("audit")(e))
+	assert.True(t, route.Project("api")(e))
+	assert.True(t, route.Or(route.Env("dev"), route.Env("prod"))(e))
+	assert.False(t, route.And(route.Env("prod"), route.Project("web"))(e))
+}
+
+func TestFilter(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: route.Filter(route.MustParse("fields.audit"), h),
+		Level:   log.DebugLevel,
+	}
+
+	l.WithField("audit", true).Info("login")
+	l.Info("hello")
+
+	assert.Equal(t, []string{"login"}, h.Messages())
+}
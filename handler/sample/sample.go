@@ -0,0 +1,126 @@
+// Package sample implements a handler which samples high-volume entries,
+// either at a fixed rate or by passing the first entries with the same level
+// and message per interval and every Mth one after that.
+package sample
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// Config for handler.
+type Config struct {
+	// Level at or above which entries always pass (default: ErrorLevel).
+	Level log.Level
+
+	// Rate is the fraction of entries passed at random, e.g. 0.1 passes one
+	// entry in ten on average (default: 1).
+	Rate float64
+
+	// First entries with the same level and message are passed per Interval,
+	// after that only every Thereafter-th one (default: 0, no limit).
+	First      int
+	Thereafter int
+	Interval   time.Duration // Interval of the counters (default: 1s)
+
+	Random func() float64   // Random returns a number in [0, 1) (default: rand.Float64)
+	Now    func() time.Time // Now returns the current time (default: time.Now)
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Level == 0 {
+		c.Level = log.ErrorLevel
+	}
+
+	if c.Rate == 0 {
+		c.Rate = 1
+	}
+
+	if c.Interval == 0 {
+		c.Interval = time.Second
+	}
+
+	if c.Random == nil {
+		c.Random = rand.Float64
+	}
+
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+	Handler log.Handler
+
+	mu      sync.Mutex
+	counts  map[string]int
+	resetAt time.Time
+	dropped atomic.Uint64
+}
+
+// New handler sampling entries passed to `h`.
+func New(h log.Handler, config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config:  config,
+		Handler: h,
+	}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	if e.Level >= h.Level.Int() || h.sample(e) {
+		return h.Handler.HandleLog(e)
+	}
+
+	h.dropped.Add(1)
+	return nil
+}
+
+// Dropped returns the number of entries dropped so far.
+func (h *Handler) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Flush implements log.Flusher.
+func (h *Handler) Flush(ctx context.Context) error {
+	return log.FlushHandler(ctx, h.Handler)
+}
+
+// Close implements log.Closer.
+func (h *Handler) Close(ctx context.Context) error {
+	return log.CloseHandler(ctx, h.Handler)
+}
+
+// sample returns whether `e` passes.
+func (h *Handler) sample(e *log.Entry) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.First > 0 {
+		now := h.Now()
+		if !now.Before(h.resetAt) {
+			h.counts = make(map[string]int)
+			h.resetAt = now.Add(h.Interval)
+		}
+
+		key := strconv.Itoa(e.Level) + "\x00" + e.Message
+		n := h.counts[key] + 1
+		h.counts[key] = n
+
+		if n > h.First && (h.Thereafter <= 0 || (n-h.First)%h.Thereafter != 0) {
+			return false
+		}
+	}
+
+	return h.Rate >= 1 || h.Random() < h.Rate
+}
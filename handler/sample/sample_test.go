@@ -0,0 +1,79 @@
+package sample_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/sample"
+)
+
+func Test(t *testing.T) {
+	now := time.Unix(0, 0)
+	h := memory.New()
+
+	s := sample.New(h, &sample.Config{
+		First:      2,
+		Thereafter: 3,
+		Now:        func() time.Time { return now },
+	})
+
+	l := &log.Logger{
+		Handler: s,
+		Level:   log.DebugLevel,
+	}
+
+	for i := 0; i < 8; i++ {
+		l.Info("hot")
+		l.Debug("hot")
+	}
+	l.Info("cold")
+	l.Error("hot")
+	l.Error("hot")
+
+	now = now.Add(time.Second)
+	l.Info("hot")
+
+	assert.Equal(t, []string{
+		"hot", "hot",
+		"hot", "hot",
+		"hot", "hot",
+		"hot", "hot",
+		"cold",
+		"hot", "hot",
+		"hot",
+	}, h.Messages())
+	assert.Equal(t, uint64(8), s.Dropped())
+}
+
+func TestRate(t *testing.T) {
+	random := []float64{0.05, 0.5, 0.09, 0.1}
+	h := memory.New()
+
+	s := sample.New(h, &sample.Config{
+		Level: log.WarnLevel,
+		Rate:  0.1,
+		Random: func() float64 {
+			v := random[0]
+			random = random[1:]
+			return v
+		},
+	})
+
+	l := &log.Logger{
+		Handler: s,
+		Level:   log.DebugLevel,
+	}
+
+	l.Info("1")
+	l.Info("2")
+	l.Warn("3")
+	l.Debug("4")
+	l.Debug("5")
+
+	assert.Equal(t, []string{"1", "3", "4"}, h.Messages())
+	assert.Equal(t, uint64(2), s.Dropped())
+}
@@ -0,0 +1,227 @@
+// Package slog bridges golog and log/slog in both directions. Handler writes
+// golog entries into any slog.Handler, SlogHandler is a slog.Handler which
+// forwards records into a golog Logger, so both APIs can share one pipeline.
+package slog
+
+import (
+	"context"
+	s "log/slog"
+	"runtime"
+
+	"github.com/socifi/golog"
+)
+
+// levels maps golog levels to slog levels, in ascending order.
+var levels = []struct {
+	golog log.Level
+	slog  s.Level
+}{
+	{log.DebugLevel, s.LevelDebug},
+	{log.InfoLevel, s.LevelInfo},
+	{log.NoticeLevel, s.LevelInfo + 2},
+	{log.WarnLevel, s.LevelWarn},
+	{log.ErrorLevel, s.LevelError},
+	{log.CriticalLevel, s.LevelError + 4},
+	{log.AlertLevel, s.LevelError + 8},
+	{log.FatalLevel, s.LevelError + 10},
+	{log.EmergencyLevel, s.LevelError + 12},
+}
+
+// SlogLevel returns the slog level of golog level `l`. Levels between the
+// known ones are rounded down, e.g. 450 becomes the level of error.
+func SlogLevel(l log.Level) s.Level {
+	v := levels[0].slog
+	for _, m := range levels {
+		if l >= m.golog {
+			v = m.slog
+		}
+	}
+	return v
+}
+
+// GologLevel returns the golog level of slog level `l`. Levels between the
+// known ones are rounded down, e.g. slog.LevelInfo+1 becomes info.
+func GologLevel(l s.Level) log.Level {
+	v := levels[0].golog
+	for _, m := range levels {
+		if l >= m.slog {
+			v = m.golog
+		}
+	}
+	return v
+}
+
+// Handler implementation.
+type Handler struct {
+	Handler s.Handler
+}
+
+// New handler writing into `h`.
+func New(h s.Handler) *Handler {
+	return &Handler{
+		Handler: h,
+	}
+}
+
+// HandleLog implements log.Handler. Fields become attributes, env, project,
+// hostname and the logger name are added when set.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	level := SlogLevel(log.Level(e.Level))
+	if !h.Handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	r := s.NewRecord(e.Timestamp, level, e.Message, 0)
+
+	for _, name := range e.Fields.Names() {
+		r.AddAttrs(s.Any(name, e.Fields.Get(name)))
+	}
+
+	for _, a := range []s.Attr{
+		s.String("env", e.Env),
+		s.String("project", e.Project),
+		s.String("hostname", e.Hostname),
+		s.String("logger", e.Name),
+	} {
+		if a.Value.String() != "" {
+			r.AddAttrs(a)
+		}
+	}
+
+	if e.Caller != nil {
+		r.AddAttrs(s.Any(s.SourceKey, &s.Source{
+			Function: e.Caller.Function,
+			File:     e.Caller.File,
+			Line:     e.Caller.Line,
+		}))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+// SlogHandler implements slog.Handler, forwarding records into a golog Logger.
+// Attributes become fields, groups become nested fields.
+type SlogHandler struct {
+	logger *log.Logger
+	fields log.Fields
+	groups []string
+}
+
+// NewSlogHandler returns a slog.Handler logging into `l`, use it with slog.New.
+func NewSlogHandler(l *log.Logger) *SlogHandler {
+	return &SlogHandler{
+		logger: l,
+		fields: log.Fields{},
+	}
+}
+
+// Enabled implements slog.Handler.
+func (h *SlogHandler) Enabled(ctx context.Context, level s.Level) bool {
+	return GologLevel(level) >= h.logger.GetLevel()
+}
+
+// Handle implements slog.Handler.
+func (h *SlogHandler) Handle(ctx context.Context, r s.Record) error {
+	fields := clone(h.fields)
+	r.Attrs(func(a s.Attr) bool {
+		addAttr(fields, h.groups, a)
+		return true
+	})
+
+	e := h.logger.WithFields(fields).WithContext(ctx)
+
+	if h.logger.ReportCaller && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Caller = &log.Caller{
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
+		}
+	}
+
+	e.Log(GologLevel(r.Level), r.Message)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *SlogHandler) WithAttrs(attrs []s.Attr) s.Handler {
+	fields := clone(h.fields)
+	for _, a := range attrs {
+		addAttr(fields, h.groups, a)
+	}
+
+	return &SlogHandler{
+		logger: h.logger,
+		fields: fields,
+		groups: h.groups,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *SlogHandler) WithGroup(name string) s.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+
+	return &SlogHandler{
+		logger: h.logger,
+		fields: h.fields,
+		groups: append(groups, name),
+	}
+}
+
+// addAttr adds attribute `a` to `fields`, nested under `groups`. Groups are
+// only created once they have an attribute, as required by slog.Handler.
+func addAttr(fields log.Fields, groups []string, a s.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(s.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == s.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+
+		if a.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], a.Key)
+		}
+
+		for _, v := range attrs {
+			addAttr(fields, groups, v)
+		}
+		return
+	}
+
+	for _, g := range groups {
+		sub, ok := fields[g].(log.Fields)
+		if !ok {
+			sub = log.Fields{}
+			fields[g] = sub
+		}
+		fields = sub
+	}
+
+	fields[a.Key] = a.Value.Any()
+}
+
+// clone returns a deep copy of `fields`, copying the nested groups.
+func clone(fields log.Fields) log.Fields {
+	c := make(log.Fields, len(fields))
+	for k, v := range fields {
+		if sub, ok := v.(log.Fields); ok {
+			v = clone(sub)
+		}
+		c[k] = v
+	}
+	return c
+}
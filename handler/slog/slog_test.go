@@ -0,0 +1,94 @@
+package slog_test
+
+import (
+	"bytes"
+	s "log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/slog"
+)
+
+func init() {
+	log.Now = func() time.Time {
+		return time.Unix(0, 0).UTC()
+	}
+}
+
+func TestLevels(t *testing.T) {
+	assert.Equal(t, s.LevelDebug, slog.SlogLevel(log.DebugLevel))
+	assert.Equal(t, s.LevelDebug, slog.SlogLevel(50))
+	assert.Equal(t, s.LevelInfo+2, slog.SlogLevel(log.NoticeLevel))
+	assert.Equal(t, s.LevelError, slog.SlogLevel(450))
+	assert.Equal(t, s.LevelError+8, slog.SlogLevel(log.AlertLevel))
+	assert.Equal(t, s.LevelError+10, slog.SlogLevel(log.FatalLevel))
+	assert.Equal(t, s.LevelError+12, slog.SlogLevel(log.EmergencyLevel))
+
+	assert.Equal(t, log.Level(log.DebugLevel), slog.GologLevel(s.LevelDebug-4))
+	assert.Equal(t, log.Level(log.InfoLevel), slog.GologLevel(s.LevelInfo+1))
+	assert.Equal(t, log.Level(log.WarnLevel), slog.GologLevel(s.LevelWarn))
+	assert.Equal(t, log.Level(log.ErrorLevel), slog.GologLevel(s.LevelError))
+
+	for _, l := range []log.Level{log.DebugLevel, log.InfoLevel, log.NoticeLevel, log.WarnLevel, log.ErrorLevel, log.CriticalLevel, log.AlertLevel, log.FatalLevel, log.EmergencyLevel} {
+		assert.Equal(t, l, slog.GologLevel(slog.SlogLevel(l)))
+	}
+}
+
+func Test(t *testing.T) {
+	var buf bytes.Buffer
+	hostname, _ := os.Hostname()
+
+	l := &log.Logger{
+		Handler: slog.New(s.NewTextHandler(&buf, &s.HandlerOptions{Level: s.LevelInfo})),
+		Level:   log.DebugLevel,
+	}
+
+	l.WithField("user", "tj").WithField("id", "123").Named("db").Info("hello")
+	l.Debug("ignored")
+	l.SetEnvProject("prod", "golog").Error("boom")
+
+	assert.Equal(t, `time=1970-01-01T00:00:00.000Z level=INFO msg=hello id=123 user=tj logger=db
+time=1970-01-01T00:00:00.000Z level=ERROR msg=boom env=prod project=golog hostname=`+hostname+`
+`, buf.String())
+}
+
+func TestSlogHandler(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:      h,
+		Level:        log.InfoLevel,
+		ReportCaller: true,
+	}
+
+	logger := s.New(slog.NewSlogHandler(l))
+	logger.Debug("ignored")
+
+	req := logger.With("user", "tj").WithGroup("req").With("method", "GET")
+	req.Warn("slow", "path", "/", s.Group("timing", "total", time.Second), s.Group("empty"))
+	logger.WithGroup("ignored").Error("boom", s.Any("", nil))
+
+	assert.Len(t, h.Entries, 2)
+
+	e := h.Entries[0]
+	assert.Equal(t, "slow", e.Message)
+	assert.Equal(t, log.WarnLevel, e.Level)
+	assert.Equal(t, log.Fields{
+		"user": "tj",
+		"req": log.Fields{
+			"method": "GET",
+			"path":   "/",
+			"timing": log.Fields{"total": time.Second},
+		},
+	}, e.Fields)
+	assert.Regexp(t, `^slog_test.go:\d+$`, e.Caller.String())
+
+	e = h.Entries[1]
+	assert.Equal(t, log.ErrorLevel, e.Level)
+	assert.Equal(t, log.Fields{}, e.Fields)
+}
@@ -0,0 +1,364 @@
+// Package syslog implements an RFC 5424 syslog handler over UDP, TCP and TLS.
+// Fields are sent as structured data, messages over TCP and TLS are framed by
+// octet counting (RFC 6587).
+package syslog
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// ErrClosed is returned for entries handled after Close.
+var ErrClosed = errors.New("syslog: handler closed")
+
+// Facility of the messages.
+type Facility int
+
+// Facilities.
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	Lpr
+	News
+	Uucp
+	Cron
+	Authpriv
+	Ftp
+	_
+	_
+	_
+	_
+	Local0
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+// Severity returns the syslog severity of `level`, from 0 for emergency to 7
+// for debug. Levels between the standard ones map to the lower severity.
+func Severity(level log.Level) int {
+	switch {
+	case level >= log.EmergencyLevel:
+		return 0
+	case level >= log.AlertLevel:
+		return 1
+	case level >= log.CriticalLevel:
+		return 2
+	case level >= log.ErrorLevel:
+		return 3
+	case level >= log.WarnLevel:
+		return 4
+	case level >= log.NoticeLevel:
+		return 5
+	case level >= log.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// Config for handler.
+type Config struct {
+	Network   string      // Network is "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6" or "tls" (default: "udp")
+	Address   string      // Address of the syslog server, e.g. "logs.example.com:514"
+	TLSConfig *tls.Config // TLSConfig of "tls" connections
+
+	// Facility of the messages, Kern is reserved for the kernel so zero means
+	// User (default: User).
+	Facility Facility
+
+	AppName  string // AppName of the messages (default: name of the executable)
+	MsgID    string // MsgID of the messages (default: none)
+	Hostname string // Hostname of entries without one (default: os.Hostname)
+
+	// SDID is the ID of the structured data element holding the fields, IDs
+	// without "@" are reserved by IANA (default: "golog@32473").
+	SDID string
+
+	Timeout time.Duration // Timeout of connecting and writing (default: 5s)
+
+	stream bool // stream is set for networks other than UDP
+}
+
+// defaults applies defaults to the config.
+func (c *Config) defaults() {
+	if c.Network == "" {
+		c.Network = "udp"
+	}
+
+	switch c.Network {
+	case "udp", "udp4", "udp6":
+	default:
+		c.stream = true
+	}
+
+	if c.Facility == Kern {
+		c.Facility = User
+	}
+
+	if c.AppName == "" {
+		c.AppName = filepath.Base(os.Args[0])
+	}
+
+	if c.Hostname == "" {
+		c.Hostname, _ = os.Hostname()
+	}
+
+	if c.SDID == "" {
+		c.SDID = "golog@32473"
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	*Config
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// New handler. The connection is established with the first entry and
+// reestablished whenever it breaks.
+func New(config *Config) *Handler {
+	config.defaults()
+	return &Handler{
+		Config: config,
+	}
+}
+
+// HandleLog implements log.Handler. A failed write over an established TCP or
+// TLS connection is retried once over a new connection, failed dials are not.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	msg := h.format(e)
+	if h.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrClosed
+	}
+
+	connected := h.conn != nil
+	err := h.write(msg)
+	if err != nil && connected && h.stream {
+		err = h.write(msg)
+	}
+	return err
+}
+
+// Close implements log.Closer, closing the connection.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	if h.conn == nil {
+		return nil
+	}
+
+	err := h.conn.Close()
+	h.conn = nil
+	return err
+}
+
+// write sends `msg` over the connection, dialing it first if needed. The
+// connection is dropped on failure. Caller must hold mu.
+func (h *Handler) write(msg []byte) error {
+	if h.conn == nil {
+		conn, err := h.dial()
+		if err != nil {
+			return err
+		}
+		h.conn = conn
+	}
+
+	h.conn.SetWriteDeadline(time.Now().Add(h.Timeout))
+	if _, err := h.conn.Write(msg); err != nil {
+		h.conn.Close()
+		h.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// dial connects to the server.
+func (h *Handler) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: h.Timeout}
+	if h.Network == "tls" {
+		return tls.DialWithDialer(dialer, "tcp", h.Address, h.TLSConfig)
+	}
+	return dialer.Dial(h.Network, h.Address)
+}
+
+// format returns the RFC 5424 message of `e`.
+func (h *Handler) format(e *log.Entry) []byte {
+	var buf bytes.Buffer
+
+	pri := int(h.Facility)*8 + Severity(log.Level(e.Level))
+	fmt.Fprintf(&buf, "<%d>1 ", pri)
+
+	if e.Timestamp.IsZero() {
+		buf.WriteString("-")
+	} else {
+		buf.WriteString(e.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"))
+	}
+
+	hostname := e.Hostname
+	if hostname == "" {
+		hostname = h.Hostname
+	}
+
+	for _, v := range []struct {
+		s   string
+		max int
+	}{
+		{hostname, 255},
+		{h.AppName, 48},
+		{strconv.Itoa(os.Getpid()), 128},
+		{h.MsgID, 32},
+	} {
+		buf.WriteByte(' ')
+		buf.WriteString(header(v.s, v.max))
+	}
+
+	buf.WriteByte(' ')
+	h.structuredData(&buf, e)
+
+	if e.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(e.Message)
+	}
+
+	return buf.Bytes()
+}
+
+// structuredData writes the element of the entry fields, env, project, name
+// and caller, or "-" if there are none.
+func (h *Handler) structuredData(buf *bytes.Buffer, e *log.Entry) {
+	params := make([][2]string, 0, len(e.Fields)+4)
+
+	for _, p := range [][2]string{
+		{"env", e.Env},
+		{"project", e.Project},
+		{"logger", e.Name},
+	} {
+		if p[1] != "" {
+			params = append(params, p)
+		}
+	}
+
+	if e.Caller != nil {
+		params = append(params, [2]string{"caller", e.Caller.String()})
+	}
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		params = append(params, [2]string{k, value(e.Fields[k])})
+	}
+
+	if len(params) == 0 {
+		buf.WriteString("-")
+		return
+	}
+
+	buf.WriteByte('[')
+	buf.WriteString(name(h.SDID))
+	for _, p := range params {
+		buf.WriteByte(' ')
+		buf.WriteString(name(p[0]))
+		buf.WriteString(`="`)
+		for _, r := range p[1] {
+			if r == '"' || r == '\\' || r == ']' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte(']')
+}
+
+// header returns `s` as a header field, printable US-ASCII of at most `max`
+// characters, or "-" if it is empty.
+func header(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+
+	b := []byte(s)
+	if len(b) > max {
+		b = b[:max]
+	}
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// name returns `s` as an SD-NAME, printable US-ASCII without '=', ' ', ']'
+// and '"' of at most 32 characters.
+func name(s string) string {
+	b := []byte(header(s, 32))
+	for i, c := range b {
+		if c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// value returns field value `v` as a string.
+func value(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
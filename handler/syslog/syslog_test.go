@@ -0,0 +1,225 @@
+package syslog_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/syslog"
+)
+
+// readFrame reads an octet-counted message.
+func readFrame(r *bufio.Reader) (string, error) {
+	n, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+
+	size, err := strconv.Atoi(strings.TrimSpace(n))
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, size)
+	_, err = io.ReadFull(r, b)
+	return string(b), err
+}
+
+func Test(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	h := syslog.New(&syslog.Config{
+		Address:  conn.LocalAddr().String(),
+		Facility: syslog.Local3,
+		AppName:  "api",
+		MsgID:    "http request",
+		Hostname: "web-1",
+	})
+	defer h.Close(context.Background())
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	assert.NoError(t, h.HandleLog(&log.Entry{
+		Level:     int(log.ErrorLevel),
+		Message:   "request failed",
+		Timestamp: ts,
+		Env:       "prod",
+		Fields:    log.Fields{"status": 500, "path": `/a"b]`, "error": map[string]interface{}{"message": "boom"}},
+	}))
+
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`<155>1 2020-01-02T03:04:05.000006Z web-1 api %d http_request [golog@32473 env="prod" error="{\"message\":\"boom\"}" path="/a\"b\]" status="500"] request failed`, os.Getpid()), string(buf[:n]))
+
+	assert.NoError(t, h.HandleLog(&log.Entry{Level: int(log.DebugLevel), Hostname: "worker-1"}))
+	n, _, err = conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`<159>1 - worker-1 api %d http_request -`, os.Getpid()), string(buf[:n]))
+}
+
+func TestUDP4(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	h := syslog.New(&syslog.Config{
+		Network:  "udp4",
+		Address:  conn.LocalAddr().String(),
+		AppName:  "api",
+		Hostname: "web-1",
+	})
+	defer h.Close(context.Background())
+
+	assert.NoError(t, h.HandleLog(&log.Entry{Level: int(log.InfoLevel), Message: "hello"}))
+
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`<14>1 - web-1 api %d - - hello`, os.Getpid()), string(buf[:n]))
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, 0, syslog.Severity(log.EmergencyLevel))
+	assert.Equal(t, 1, syslog.Severity(log.FatalLevel))
+	assert.Equal(t, 4, syslog.Severity(log.WarnLevel))
+	assert.Equal(t, 4, syslog.Severity(350))
+	assert.Equal(t, 6, syslog.Severity(log.InfoLevel))
+	assert.Equal(t, 7, syslog.Severity(log.DebugLevel))
+}
+
+func TestTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	h := syslog.New(&syslog.Config{
+		Network: "tcp",
+		Address: ln.Addr().String(),
+		AppName: "api",
+	})
+	defer h.Close(context.Background())
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	l.Info("one")
+	l.Info("two")
+
+	conn, err := ln.Accept()
+	assert.NoError(t, err)
+	r := bufio.NewReader(conn)
+
+	for _, msg := range []string{"one", "two"} {
+		frame, err := readFrame(r)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasSuffix(frame, " - "+msg), frame)
+	}
+
+	// the server goes away, the handler reconnects
+	conn.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	var next net.Conn
+	for next == nil {
+		l.Info("three")
+		select {
+		case next = <-accepted:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	defer next.Close()
+
+	frame, err := readFrame(bufio.NewReader(next))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(frame, " - three"), frame)
+}
+
+func TestTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(nil)
+	server.StartTLS()
+	defer server.Close()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", server.TLS)
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	h := syslog.New(&syslog.Config{
+		Network:   "tls",
+		Address:   ln.Addr().String(),
+		TLSConfig: server.Client().Transport.(*http.Transport).TLSClientConfig,
+	})
+	defer h.Close(context.Background())
+
+	done := make(chan string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(done)
+			return
+		}
+		defer conn.Close()
+		frame, _ := readFrame(bufio.NewReader(conn))
+		done <- frame
+	}()
+
+	assert.NoError(t, h.HandleLog(&log.Entry{Level: int(log.InfoLevel), Message: "secure"}))
+	assert.True(t, strings.HasSuffix(<-done, " - secure"))
+}
+
+func TestDialError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	accepted := make(chan struct{}, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+			accepted <- struct{}{}
+		}
+	}()
+
+	// the TLS handshake with a plain TCP server fails
+	h := syslog.New(&syslog.Config{
+		Network: "tls",
+		Address: ln.Addr().String(),
+	})
+	defer h.Close(context.Background())
+
+	assert.Error(t, h.HandleLog(&log.Entry{Message: "hello"}))
+	<-accepted
+
+	select {
+	case <-accepted:
+		t.Fatal("failed dial was retried")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
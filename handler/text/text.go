@@ -0,0 +1,153 @@
+// Package text implements a development-friendly textual handler, printing
+// aligned entries with time relative to the start, sorted fields and colors
+// derived from the level.
+package text
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/socifi/golog"
+)
+
+// Default handler outputting to stderr.
+var Default = New(os.Stderr)
+
+// colors.
+const (
+	none    = ""
+	red     = "\033[31m"
+	boldRed = "\033[1;31m"
+	yellow  = "\033[33m"
+	blue    = "\033[34m"
+	gray    = "\033[37m"
+	reset   = "\033[0m"
+)
+
+// Color returns the color of level `l`, levels between the known ones get
+// the color of the closest lower one, so custom levels are colored as well.
+func Color(l log.Level) string {
+	switch {
+	case l >= log.CriticalLevel:
+		return boldRed
+	case l >= log.ErrorLevel:
+		return red
+	case l >= log.WarnLevel:
+		return yellow
+	case l >= log.InfoLevel:
+		return blue
+	default:
+		return gray
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	Writer io.Writer
+	Color  bool // Color enables colors, by default only when Writer is a terminal
+
+	mu    sync.Mutex
+	start time.Time
+}
+
+// New handler.
+func New(w io.Writer) *Handler {
+	return &Handler{
+		Writer: w,
+		Color:  isTerminal(w),
+		start:  log.Now(),
+	}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e *log.Entry) error {
+	color, end := none, none
+	if h.Color {
+		color, end = Color(log.Level(e.Level)), reset
+	}
+
+	var b strings.Builder
+
+	elapsed := e.Timestamp.Sub(h.start) / time.Second
+	fmt.Fprintf(&b, "%s%9s%s[%04d] ", color, strings.ToUpper(e.LevelName), end, elapsed)
+
+	msg := e.Message
+	if e.Name != "" {
+		msg = e.Name + ": " + msg
+	}
+	fmt.Fprintf(&b, "%-25s", msg)
+
+	fields := flatten("", e.Fields, log.Fields{})
+	if e.Caller != nil {
+		fields["caller"] = e.Caller.String()
+	}
+
+	for _, name := range fields.Names() {
+		fmt.Fprintf(&b, " %s%s%s=%s", color, name, end, format(fields[name]))
+	}
+
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := io.WriteString(h.Writer, b.String())
+	return err
+}
+
+// flatten copies `fields` into `dst`, nested maps are rendered with
+// dotted names, e.g. "error.message".
+func flatten(prefix string, fields map[string]interface{}, dst log.Fields) log.Fields {
+	for k, v := range fields {
+		switch m := v.(type) {
+		case log.Fields:
+			flatten(prefix+k+".", m, dst)
+		case map[string]interface{}:
+			flatten(prefix+k+".", m, dst)
+		default:
+			dst[prefix+k] = v
+		}
+	}
+
+	return dst
+}
+
+// format returns the value quoted when it would break the layout.
+func format(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// isTerminal returns whether `w` is a terminal which supports colors.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Flush implements log.Flusher, see log.FlushWriter.
+func (h *Handler) Flush(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.FlushWriter(h.Writer)
+}
+
+// Close implements log.Closer, see log.CloseWriter.
+func (h *Handler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return log.CloseWriter(h.Writer)
+}
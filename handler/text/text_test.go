@@ -0,0 +1,61 @@
+package text_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/text"
+)
+
+func init() {
+	log.Now = func() time.Time {
+		return time.Unix(0, 0).UTC()
+	}
+}
+
+func Test(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler:   text.New(&buf),
+		Level:     log.DebugLevel,
+		OmitStack: true,
+	}
+
+	l.WithField("user", "tj").WithField("id", "123").Info("hello")
+	l.Named("db").WithField("query", "select 1").Debug("world")
+	l.WithError(errors.New("boom")).Emergency("upload failed")
+
+	expected := `     INFO[0000] hello                     id=123 user=tj
+    DEBUG[0000] db: world                 query="select 1"
+EMERGENCY[0000] upload failed             error.message=boom error.type=*errors.errorString
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestColor(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := text.New(&buf)
+	assert.False(t, h.Color)
+	h.Color = true
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	l.WithField("user", "tj").Warn("hello")
+	l.Log(450, "custom")
+
+	expected := "\033[33m     WARN\033[0m[0000] hello                     \033[33muser\033[0m=tj\n" +
+		"\033[31mLEVEL(450)\033[0m[0000] custom                   \n"
+
+	assert.Equal(t, expected, buf.String())
+}
@@ -1,11 +1,17 @@
 package log
 
+import "context"
+
 // Interface represents the API of both Logger and Entry.
 type Interface interface {
 	SetEnvProject(env string, project string) *Entry
 	WithFields(fields Fielder) *Entry
 	WithField(key string, value interface{}) *Entry
 	WithError(err error) *Entry
+	WithContext(ctx context.Context) *Entry
+	Named(name string) *Entry
+	Log(level Level, msg string)
+	Logf(level Level, msg string, v ...interface{})
 	/********* Interface simple *********/
 	/* WARNING: This part is autogenerated! Do not change! */
 
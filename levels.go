@@ -3,12 +3,18 @@ package log
 import (
 	"bytes"
 	"errors"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // ErrInvalidLevel is returned if the severity level is invalid.
 var ErrInvalidLevel = errors.New("invalid level")
 
+// ErrLevelExists is returned if the level name is already registered with a different code.
+var ErrLevelExists = errors.New("level already registered")
+
 // Level of severity.
 type Level int
 
@@ -26,24 +32,28 @@ const (
 	CriticalLevel  = 500
 	AlertLevel     = 550
 	FatalLevel     = 600
-	EmergencyLevel = 600
+	EmergencyLevel = 700
 
 /* END OF WARNING */
 /********* End Level numbers *********/
 )
 
-var levelNames = [...]string{
+// levelsMu guards levelNames and levelStrings which may be extended at runtime by RegisterLevel.
+var levelsMu sync.RWMutex
+
+var levelNames = map[Level]string{
 	/********* Level no:name *********/
 	/* WARNING: This part is autogenerated! Do not change! */
 
-	DebugLevel:    "debug",
-	InfoLevel:     "info",
-	NoticeLevel:   "notice",
-	WarnLevel:     "warn",
-	ErrorLevel:    "error",
-	CriticalLevel: "critical",
-	AlertLevel:    "alert",
-	FatalLevel:    "fatal",
+	DebugLevel:     "debug",
+	InfoLevel:      "info",
+	NoticeLevel:    "notice",
+	WarnLevel:      "warn",
+	ErrorLevel:     "error",
+	CriticalLevel:  "critical",
+	AlertLevel:     "alert",
+	FatalLevel:     "fatal",
+	EmergencyLevel: "emergency",
 
 	/* END OF WARNING */
 	/********* End Level no:name *********/
@@ -67,9 +77,48 @@ var levelStrings = map[string]Level{
 	/********* End Level name:no *********/
 }
 
-// String returns level string.
+// RegisterLevel adds a custom level `name` with severity `code` to the level
+// registry, so it can be parsed, printed and logged with Log and Logf. When
+// the code already has a name, `name` becomes its alias for parsing only.
+// Registering the same name and code twice is a no-op. Thread safe.
+func RegisterLevel(name string, code int) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, " \t\r\n,=") {
+		return InvalidLevel, ErrInvalidLevel
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	l := Level(code)
+	if v, ok := levelStrings[name]; ok {
+		if v != l {
+			return InvalidLevel, ErrLevelExists
+		}
+		return l, nil
+	}
+
+	levelStrings[name] = l
+	if _, ok := levelNames[l]; !ok {
+		levelNames[l] = name
+	}
+
+	return l, nil
+}
+
+// String returns level string. Levels which are not registered are
+// printed as "level(N)", which ParseLevel accepts as well unless N is
+// negative, e.g. InvalidLevel.
 func (l Level) String() string {
-	return levelNames[int(l)]
+	levelsMu.RLock()
+	name, ok := levelNames[l]
+	levelsMu.RUnlock()
+
+	if !ok {
+		return "level(" + strconv.Itoa(int(l)) + ")"
+	}
+
+	return name
 }
 
 // Int returns level code.
@@ -82,6 +131,23 @@ func (l Level) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + l.String() + `"`), nil
 }
 
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so levels can be
+// read from YAML, TOML, flags (flag.TextVar) or environment variables.
+func (l *Level) UnmarshalText(b []byte) error {
+	v, err := ParseLevel(string(b))
+	if err != nil {
+		return err
+	}
+
+	*l = v
+	return nil
+}
+
 // UnmarshalJSON implementation.
 func (l *Level) UnmarshalJSON(b []byte) error {
 	v, err := ParseLevel(string(bytes.Trim(b, `"`)))
@@ -93,14 +159,28 @@ func (l *Level) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// ParseLevel parses level string.
+// ParseLevel parses level string. Besides the registered level names it
+// accepts level codes, either plain ("350") or as printed by String ("level(350)").
 func ParseLevel(s string) (Level, error) {
-	l, ok := levelStrings[strings.ToLower(s)]
-	if !ok {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	levelsMu.RLock()
+	l, ok := levelStrings[s]
+	levelsMu.RUnlock()
+	if ok {
+		return l, nil
+	}
+
+	if strings.HasPrefix(s, "level(") && strings.HasSuffix(s, ")") {
+		s = s[len("level(") : len(s)-1]
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
 		return InvalidLevel, ErrInvalidLevel
 	}
 
-	return l, nil
+	return Level(n), nil
 }
 
 // MustParseLevel parses level string or panics.
@@ -112,3 +192,47 @@ func MustParseLevel(s string) Level {
 
 	return l
 }
+
+// AtomicLevel is a level which can be read and changed concurrently, e.g.
+// while other goroutines are logging. The zero value is level 0.
+type AtomicLevel struct {
+	v atomic.Int64
+}
+
+// NewAtomicLevel returns a new AtomicLevel set to `l`.
+func NewAtomicLevel(l Level) *AtomicLevel {
+	a := &AtomicLevel{}
+	a.SetLevel(l)
+	return a
+}
+
+// Level returns the current level.
+func (a *AtomicLevel) Level() Level {
+	return Level(a.v.Load())
+}
+
+// SetLevel changes the level.
+func (a *AtomicLevel) SetLevel(l Level) {
+	a.v.Store(int64(l))
+}
+
+// String returns the current level string.
+func (a *AtomicLevel) String() string {
+	return a.Level().String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a *AtomicLevel) MarshalText() ([]byte, error) {
+	return a.Level().MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *AtomicLevel) UnmarshalText(b []byte) error {
+	v, err := ParseLevel(string(b))
+	if err != nil {
+		return err
+	}
+
+	a.SetLevel(v)
+	return nil
+}
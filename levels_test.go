@@ -2,6 +2,7 @@ package log
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"testing"
 
@@ -19,6 +20,7 @@ func TestParseLevel(t *testing.T) {
 		{"warn", WarnLevel, 2},
 		{"error", ErrorLevel, 4},
 		{"fatal", FatalLevel, 5},
+		{"emergency", EmergencyLevel, 6},
 	}
 
 	for _, c := range cases {
@@ -27,6 +29,7 @@ func TestParseLevel(t *testing.T) {
 			msg := fmt.Sprintf("parse %s", c.String)
 			assert.NoError(t, err, msg)
 			assert.Equal(t, c.Level, l)
+			assert.Equal(t, c.String, l.String())
 		})
 	}
 
@@ -60,3 +63,87 @@ func TestLevel_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, InfoLevel, e.Level)
 }
+
+func TestRegisterLevel(t *testing.T) {
+	trace, err := RegisterLevel("Trace", 50)
+	assert.NoError(t, err)
+	assert.Equal(t, Level(50), trace)
+	assert.Equal(t, "trace", trace.String())
+
+	l, err := ParseLevel("TRACE")
+	assert.NoError(t, err)
+	assert.Equal(t, trace, l)
+
+	b, err := json.Marshal(trace)
+	assert.NoError(t, err)
+	assert.Equal(t, `"trace"`, string(b))
+
+	t.Run("again", func(t *testing.T) {
+		l, err := RegisterLevel("trace", 50)
+		assert.NoError(t, err)
+		assert.Equal(t, trace, l)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		l, err := RegisterLevel("trace", 60)
+		assert.Equal(t, ErrLevelExists, err)
+		assert.Equal(t, InvalidLevel, l)
+	})
+
+	t.Run("alias", func(t *testing.T) {
+		l, err := RegisterLevel("warning", WarnLevel)
+		assert.NoError(t, err)
+		assert.Equal(t, Level(WarnLevel), l)
+		assert.Equal(t, "warn", l.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := RegisterLevel("", 70)
+		assert.Equal(t, ErrInvalidLevel, err)
+	})
+}
+
+func TestLevel_String(t *testing.T) {
+	assert.Equal(t, "info", Level(InfoLevel).String())
+	assert.Equal(t, "level(150)", Level(150).String())
+	assert.Equal(t, "level(-1)", InvalidLevel.String())
+	assert.Equal(t, "level(100000)", Level(100000).String())
+}
+
+func TestParseLevel_code(t *testing.T) {
+	cases := map[string]Level{
+		"150":        150,
+		"level(150)": 150,
+		" Info ":     InfoLevel,
+		"200":        InfoLevel,
+	}
+
+	for s, expect := range cases {
+		l, err := ParseLevel(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expect, l, s)
+	}
+
+	for _, s := range []string{"-1", "level(-1)", "level(abc)", ""} {
+		_, err := ParseLevel(s)
+		assert.Equal(t, ErrInvalidLevel, err, s)
+	}
+}
+
+func TestLevel_MarshalText(t *testing.T) {
+	b, err := Level(ErrorLevel).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "error", string(b))
+
+	var l Level
+	assert.NoError(t, l.UnmarshalText([]byte("warn")))
+	assert.Equal(t, Level(WarnLevel), l)
+
+	assert.Equal(t, ErrInvalidLevel, l.UnmarshalText([]byte("loud")))
+	assert.Equal(t, Level(WarnLevel), l)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.TextVar(&l, "level", Level(InfoLevel), "log level")
+	assert.NoError(t, fs.Parse([]string{"-level", "debug"}))
+	assert.Equal(t, Level(DebugLevel), l)
+}
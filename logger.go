@@ -1,8 +1,15 @@
 package log
 
 import (
-	stdlog "log"
+	"context"
+	"fmt"
+	"io"
+	"os"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // assert interface compliance.
@@ -55,10 +62,243 @@ type Handler interface {
 	HandleLog(*Entry) error
 }
 
+// Flusher is implemented by handlers which buffer entries, Flush writes out
+// everything handled so far, e.g. at the end of a serverless invocation.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by handlers holding resources such as files or
+// connections, Close flushes the handler and releases them. The handler must
+// not be used after Close.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// FlushHandler flushes `h` if it implements Flusher.
+func FlushHandler(ctx context.Context, h Handler) error {
+	if f, ok := h.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// CloseHandler closes `h` if it implements Closer.
+func CloseHandler(ctx context.Context, h Handler) error {
+	if c, ok := h.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// FlushWriter flushes `w` if it is buffered, i.e. it has a Flush() error
+// method, e.g. for handlers encoding entries into a writer.
+func FlushWriter(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// CloseWriter flushes `w` and closes it if it is an io.Closer other than
+// stdout or stderr.
+func CloseWriter(w io.Writer) error {
+	if err := FlushWriter(w); err != nil {
+		return err
+	}
+
+	if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+		return c.Close()
+	}
+	return nil
+}
+
 // Logger represents a logger with configurable Level and Handler.
+//
+// Level and Handler are only the initial values, once the logger is in use
+// they must be changed with SetLevel and SetHandler, which are thread safe.
+//
+// Entries of named loggers (see Named) are checked against the level of the
+// longest matching name set by SetNamedLevel or SetNamedLevels instead, if any.
+//
+// ReportCaller records the file, line and function which logged the entry
+// in Entry.Caller, unless the entry has its caller set already (e.g. by
+// adapters of other logging APIs). It is opt-in as looking up the caller is
+// expensive.
+//
+// WithError records the stack trace carried by the error or its causes. When
+// there is none, the current stack is captured unless OmitStack is set, as
+// capturing it on every error is expensive.
+//
+// When ExitOnFatal is set, entries at FatalLevel or above terminate the
+// program with Exit(1) once the handler returns and is flushed, waiting at most
+// fatalFlushTimeout, running all exit handlers first, just like log.Fatal does
+// in the stdlib. The program exits even when the entry is filtered out by the
+// level of the logger.
 type Logger struct {
-	Handler Handler
-	Level   Level
+	Handler      Handler
+	Level        Level
+	ReportCaller bool
+	OmitStack    bool
+	ExitOnFatal  bool
+
+	once    sync.Once
+	level   AtomicLevel
+	handler atomic.Value
+	mu      sync.Mutex
+	named   atomic.Value
+}
+
+// fatalFlushTimeout limits flushing the handler before exiting on fatal entries.
+const fatalFlushTimeout = 5 * time.Second
+
+// handlerValue wraps handlers so that handlers of different types can be
+// stored in the same atomic.Value.
+type handlerValue struct {
+	Handler
+}
+
+// init copies the initial Level and Handler into their atomic counterparts.
+func (l *Logger) init() {
+	l.once.Do(func() {
+		l.level.SetLevel(l.Level)
+		l.handler.Store(handlerValue{l.Handler})
+		l.named.Store(map[string]Level{})
+	})
+}
+
+// GetLevel returns the current level.
+func (l *Logger) GetLevel() Level {
+	l.init()
+	return l.level.Level()
+}
+
+// SetLevel changes the level. Thread safe.
+func (l *Logger) SetLevel(level Level) {
+	l.init()
+	l.level.SetLevel(level)
+}
+
+// AtomicLevel returns the level used by the logger, so it can be shared
+// with code which changes it at runtime.
+func (l *Logger) AtomicLevel() *AtomicLevel {
+	l.init()
+	return &l.level
+}
+
+// Flush flushes the handler, see Flusher.
+func (l *Logger) Flush(ctx context.Context) error {
+	return FlushHandler(ctx, l.GetHandler())
+}
+
+// Close closes the handler, see Closer.
+func (l *Logger) Close(ctx context.Context) error {
+	return CloseHandler(ctx, l.GetHandler())
+}
+
+// SetNamedLevel sets the minimum level of logger `name` and its descendants,
+// e.g. "db" applies to both "db" and "db.pool". Thread safe.
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	l.init()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	named := map[string]Level{}
+	for k, v := range l.named.Load().(map[string]Level) {
+		named[k] = v
+	}
+	named[name] = level
+
+	l.named.Store(named)
+}
+
+// SetNamedLevels replaces all named levels with the ones in `spec`, such as
+// "db=debug,http=warn". A level without a name, e.g. "info,db=debug", sets
+// the level of the logger itself. Thread safe.
+func (l *Logger) SetNamedLevels(spec string) error {
+	named, err := ParseNamedLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	l.init()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level, ok := named[""]; ok {
+		l.level.SetLevel(level)
+		delete(named, "")
+	}
+
+	l.named.Store(named)
+	return nil
+}
+
+// ParseNamedLevels parses comma separated "name=level" pairs. A level
+// without a name is returned under the empty name.
+func ParseNamedLevels(spec string) (map[string]Level, error) {
+	named := map[string]Level{}
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		var name, s string
+		if i := strings.Index(pair, "="); i >= 0 {
+			name, s = strings.TrimSpace(pair[:i]), pair[i+1:]
+			if name == "" {
+				return nil, fmt.Errorf("missing logger name in %q", pair)
+			}
+		} else {
+			s = pair
+		}
+
+		level, err := ParseLevel(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s in %q", err, pair)
+		}
+
+		named[name] = level
+	}
+
+	return named, nil
+}
+
+// levelFor returns the minimum level of logger `name`.
+func (l *Logger) levelFor(name string) Level {
+	named := l.named.Load().(map[string]Level)
+	if name == "" || len(named) == 0 {
+		return l.level.Level()
+	}
+
+	match := ""
+	level := l.level.Level()
+	for prefix, v := range named {
+		if len(prefix) <= len(match) {
+			continue
+		}
+
+		if name == prefix || strings.HasPrefix(name, prefix+".") {
+			match = prefix
+			level = v
+		}
+	}
+
+	return level
+}
+
+// GetHandler returns the current handler.
+func (l *Logger) GetHandler() Handler {
+	l.init()
+	return l.handler.Load().(handlerValue).Handler
+}
+
+// SetHandler replaces the handler. Thread safe.
+func (l *Logger) SetHandler(h Handler) {
+	l.init()
+	l.handler.Store(handlerValue{h})
 }
 
 // WithFields returns a new entry with `fields` set.
@@ -84,6 +324,26 @@ func (l *Logger) WithError(err error) *Entry {
 	return NewEntry(l).WithError(err)
 }
 
+// WithContext returns a new entry with `ctx` attached.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	return NewEntry(l).WithContext(ctx)
+}
+
+// Named returns a new entry of logger `name`, see Entry.Named.
+func (l *Logger) Named(name string) *Entry {
+	return NewEntry(l).Named(name)
+}
+
+// Log logs message at the given level, which may be any level registered with RegisterLevel.
+func (l *Logger) Log(level Level, msg string) {
+	NewEntry(l).Log(level, msg)
+}
+
+// Logf logs formatted message at the given level.
+func (l *Logger) Logf(level Level, msg string, v ...interface{}) {
+	NewEntry(l).Logf(level, msg, v...)
+}
+
 /********* Log simple *********/
 /* WARNING: This part is autogenerated! Do not change! */
 
@@ -196,11 +456,30 @@ func (l *Logger) Trace(msg string) *Entry {
 // to bypass the overhead in Entry methods when the level is not
 // met.
 func (l *Logger) log(level Level, e *Entry, msg string) {
-	if level < l.Level {
-		return
+	l.init()
+	if level >= l.levelFor(e.Name) {
+		l.handle(level, e, msg)
+	}
+
+	// exit even when the entry is filtered out, just like log.Fatal
+	if l.ExitOnFatal && level >= FatalLevel {
+		ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		if err := l.Flush(ctx); err != nil {
+			stdPrintln(e, fmt.Sprintf("error flushing: %s", err))
+		}
+		cancel()
+		Exit(1)
+	}
+}
+
+// handle passes the finalized entry to the handler.
+func (l *Logger) handle(level Level, e *Entry, msg string) {
+	entry := e.finalize(level, msg)
+	if l.ReportCaller && entry.Caller == nil {
+		entry.Caller = findCaller()
 	}
 
-	if err := l.Handler.HandleLog(e.finalize(level, msg)); err != nil {
-		stdlog.Printf("error logging: %s", err)
+	if err := l.GetHandler().HandleLog(entry); err != nil {
+		stdPrintln(entry, fmt.Sprintf("error logging: %s", err))
 	}
 }
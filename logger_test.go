@@ -1,13 +1,18 @@
 package log_test
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/socifi/golog"
 	"github.com/socifi/golog/handler/discard"
+	"github.com/socifi/golog/handler/level"
 	"github.com/socifi/golog/handler/memory"
+	"github.com/socifi/golog/handler/multi"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -246,3 +251,208 @@ func BenchmarkLogger_large(b *testing.B) {
 			WithError(err).Error("upload failed")
 	}
 }
+
+func TestLogger_Log(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	audit, err := log.RegisterLevel("audit", 350)
+	assert.NoError(t, err)
+
+	l.Log(audit, "login")
+	l.WithField("user", "tobi").Logf(audit, "logged in %s", "Tobi")
+	l.Log(log.DebugLevel, "uploading")
+
+	assert.Equal(t, 2, len(h.Entries))
+
+	e := h.Entries[1]
+	assert.Equal(t, "logged in Tobi", e.Message)
+	assert.Equal(t, 350, e.Level)
+	assert.Equal(t, "audit", e.LevelName)
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	l.Debug("uploading")
+	l.SetLevel(log.DebugLevel)
+	l.Debug("uploading")
+	l.AtomicLevel().SetLevel(log.ErrorLevel)
+	l.Warn("upload retry")
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, log.Level(log.ErrorLevel), l.GetLevel())
+}
+
+func TestLogger_concurrent(t *testing.T) {
+	a := memory.New()
+	b := memory.New()
+
+	l := &log.Logger{
+		Handler: a,
+		Level:   log.InfoLevel,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.WithField("j", j).Info("upload")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		l.SetLevel(log.DebugLevel)
+		l.SetHandler(b)
+		l.SetLevel(log.InfoLevel)
+		l.SetHandler(a)
+	}
+
+	wg.Wait()
+	assert.Equal(t, 1000, len(a.Entries)+len(b.Entries))
+}
+
+func TestLogger_Named(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	assert.NoError(t, l.SetNamedLevels("db=debug, http=warn"))
+
+	db := l.Named("db")
+	pool := db.WithField("size", 10).Named("pool")
+
+	db.Debug("query")
+	pool.Debug("acquire")
+	l.Named("dbx").Debug("ignored")
+	l.Named("http").Info("ignored")
+	l.Named("http").Named("router").Warn("not found")
+	l.Debug("ignored")
+
+	assert.Equal(t, 3, len(h.Entries))
+	assert.Equal(t, "db", h.Entries[0].Name)
+	assert.Equal(t, "db.pool", h.Entries[1].Name)
+	assert.Equal(t, log.Fields{"size": 10}, h.Entries[1].Fields)
+	assert.Equal(t, "http.router", h.Entries[2].Name)
+
+	l.SetNamedLevel("db.pool", log.ErrorLevel)
+	pool.Warn("ignored")
+	db.Debug("query")
+	assert.Equal(t, 4, len(h.Entries))
+
+	assert.NoError(t, l.SetNamedLevels("error"))
+	assert.Equal(t, log.Level(log.ErrorLevel), l.GetLevel())
+	db.Warn("ignored")
+	assert.Equal(t, 4, len(h.Entries))
+}
+
+func TestParseNamedLevels(t *testing.T) {
+	named, err := log.ParseNamedLevels("info, db=debug,,http.client=level(350)")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]log.Level{
+		"":            log.InfoLevel,
+		"db":          log.DebugLevel,
+		"http.client": 350,
+	}, named)
+
+	_, err = log.ParseNamedLevels("db=loud")
+	assert.EqualError(t, err, `invalid level in "db=loud"`)
+
+	_, err = log.ParseNamedLevels("=debug")
+	assert.Error(t, err)
+}
+
+func TestLogger_ReportCaller(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:      h,
+		Level:        log.InfoLevel,
+		ReportCaller: true,
+	}
+
+	var lines []int
+	line := func() int {
+		_, _, n, _ := runtime.Caller(1)
+		return n + 1
+	}
+
+	lines = append(lines, line())
+	l.Info("logger")
+	lines = append(lines, line())
+	l.Infof("logger %s", "formatted")
+	lines = append(lines, line())
+	l.WithField("file", "sloth.png").Warn("entry")
+	lines = append(lines, line())
+	l.Named("db").Errorf("entry %s", "formatted")
+	lines = append(lines, line())
+	l.Log(log.InfoLevel, "generic")
+	lines = append(lines, line())
+	func() (err error) { defer l.Trace("trace").Stop(&err); return nil }()
+	lines = append(lines, lines[len(lines)-1])
+
+	log.SetHandler(h)
+	log.Log.(*log.Logger).ReportCaller = true
+	defer func() { log.Log.(*log.Logger).ReportCaller = false }()
+
+	lines = append(lines, line())
+	log.Info("pkg")
+	lines = append(lines, line())
+	log.Infof("pkg %s", "formatted")
+
+	assert.Equal(t, len(lines), len(h.Entries))
+
+	for i, e := range h.Entries {
+		assert.NotNil(t, e.Caller, e.Message)
+		assert.Equal(t, lines[i], e.Caller.Line, e.Message)
+		assert.Equal(t, fmt.Sprintf("logger_test.go:%d", lines[i]), e.Caller.String())
+		assert.Contains(t, e.Caller.Function, "golog_test.TestLogger_ReportCaller", e.Message)
+	}
+}
+
+// lifecycle records calls of Flush and Close.
+type lifecycle struct {
+	*memory.Handler
+	calls []string
+}
+
+func (h *lifecycle) Flush(ctx context.Context) error {
+	h.calls = append(h.calls, "flush")
+	return nil
+}
+
+func (h *lifecycle) Close(ctx context.Context) error {
+	h.calls = append(h.calls, "close")
+	return fmt.Errorf("boom")
+}
+
+func TestLogger_Flush(t *testing.T) {
+	a := &lifecycle{Handler: memory.New()}
+	b := &lifecycle{Handler: memory.New()}
+
+	l := &log.Logger{
+		Handler: multi.New(level.New(a, log.ErrorLevel), memory.New(), b),
+		Level:   log.InfoLevel,
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, l.Flush(ctx))
+	assert.EqualError(t, l.Close(ctx), "handler 0 (*level.Handler): boom\nhandler 2 (*log_test.lifecycle): boom")
+	assert.Equal(t, []string{"flush", "close"}, a.calls)
+	assert.Equal(t, []string{"flush", "close"}, b.calls)
+}
@@ -1,16 +1,18 @@
 package log
 
+import "context"
+
 // Log represents default log
 var Log Interface = &Logger{
 	Handler: HandlerFunc(handleStdLog),
 	Level:   InfoLevel,
 }
 
-// SetHandler sets the handler. This is not thread-safe.
+// SetHandler sets the handler. Thread safe.
 // The default handler outputs to the stdlib log.
 func SetHandler(h Handler) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Handler = h
+		logger.SetHandler(h)
 	}
 }
 
@@ -19,18 +21,47 @@ func SetEnvProject(env string, project string) *Entry {
 	return Log.SetEnvProject(env, project)
 }
 
-// SetLevel sets the log level. This is not thread-safe.
+// SetLevel sets the log level. Thread safe.
 func SetLevel(l Level) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Level = l
+		logger.SetLevel(l)
 	}
 }
 
-// SetLevelFromString sets the log level from a string, panicing when invalid. This is not thread-safe.
+// SetLevelFromString sets the log level from a string, panicing when invalid. Thread safe.
 func SetLevelFromString(s string) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Level = MustParseLevel(s)
+		logger.SetLevel(MustParseLevel(s))
+	}
+}
+
+// SetNamedLevels sets the levels of named loggers from a spec such as "db=debug,http=warn". Thread safe.
+func SetNamedLevels(spec string) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.SetNamedLevels(spec)
+	}
+	return nil
+}
+
+// Flush flushes the handler, see Flusher.
+func Flush(ctx context.Context) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.Flush(ctx)
 	}
+	return nil
+}
+
+// Close closes the handler, see Closer.
+func Close(ctx context.Context) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.Close(ctx)
+	}
+	return nil
+}
+
+// Named returns a new entry of logger `name`.
+func Named(name string) *Entry {
+	return Log.Named(name)
 }
 
 // WithFields returns a new entry with `fields` set.
@@ -48,6 +79,11 @@ func WithError(err error) *Entry {
 	return Log.WithError(err)
 }
 
+// WithContext returns a new entry with `ctx` attached.
+func WithContext(ctx context.Context) *Entry {
+	return Log.WithContext(ctx)
+}
+
 /********* Pkg wide simple *********/
 /* WARNING: This part is autogenerated! Do not change! */
 
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// maxLineSize is the size of a partial line after which it is logged without
+// waiting for the newline.
+const maxLineSize = 64 * 1024
+
+// writer turns each line written into it into an entry.
+type writer struct {
+	entry *Entry
+	level Level
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write implements io.Writer. Partial lines are kept until they are completed
+// by a following write.
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) >= maxLineSize {
+		w.log(w.buf)
+		w.buf = nil
+	}
+
+	return len(p), nil
+}
+
+// log the line, skipping empty ones.
+func (w *writer) log(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return
+	}
+
+	writing.Add(1)
+	defer writing.Add(-1)
+	w.entry.Log(w.level, string(line))
+}
+
+// Writer returns an io.Writer which logs each written line as a message
+// at `level`, e.g. for libraries which only accept an io.Writer.
+//
+// The default handler prints entries written through Writer straight to
+// stderr instead of the stdlib log, as the output of the stdlib log may be
+// the writer itself.
+func (e *Entry) Writer(level Level) io.Writer {
+	entry := e.WithFields(Fields{})
+	entry.viaWriter = true
+
+	return &writer{
+		entry: entry,
+		level: level,
+	}
+}
+
+// Writer returns an io.Writer which logs each written line as a message
+// at `level`, e.g. for libraries which only accept an io.Writer.
+func (l *Logger) Writer(level Level) io.Writer {
+	return NewEntry(l).Writer(level)
+}
+
+// NewStdLogger returns a stdlib logger writing into `l` at `level`, e.g. for
+// http.Server.ErrorLog. The stdlib logger adds no prefix nor timestamp.
+func NewStdLogger(l *Logger, level Level) *stdlog.Logger {
+	return stdlog.New(l.Writer(level), "", 0)
+}
+
+// writing counts the lines being logged by writers. While it is not zero, the
+// stdlib log may be holding its lock and writing into golog.
+var writing atomic.Int32
+
+// StdPrintf prints a diagnostic message of a handler, such as a failed
+// flush, to the stdlib log. While the stdlib log writes into golog, the
+// message is printed to stderr in the format of the stdlib log instead, as
+// printing it to the stdlib log would call the handler again or never return.
+func StdPrintf(format string, v ...interface{}) {
+	stdPrint(false, fmt.Sprintf(format, v...))
+}
+
+// stdPrintln writes `s` about entry `e` to the stdlib log, see StdPrintf.
+// Entries written through Writer are always printed to stderr, since the
+// stdlib log may be writing them.
+func stdPrintln(e *Entry, s string) {
+	stdPrint(e.viaWriter, s)
+}
+
+// stdPrint prints `s` to the stdlib log, or to stderr if `viaWriter` is set
+// or the stdlib log writes into golog. The output of the stdlib log is only
+// looked up when no writer is logging, as the lookup takes its lock.
+func stdPrint(viaWriter bool, s string) {
+	if viaWriter || writing.Load() > 0 {
+		stdlog.New(os.Stderr, stdlog.Prefix(), stdlog.Flags()).Println(s)
+		return
+	}
+
+	if _, ok := stdlog.Writer().(*writer); ok {
+		stdlog.New(os.Stderr, stdlog.Prefix(), stdlog.Flags()).Println(s)
+		return
+	}
+
+	stdlog.Println(s)
+}
@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	stdlog "log"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/socifi/golog"
+	"github.com/socifi/golog/handler/memory"
+)
+
+func TestLogger_Writer(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.InfoLevel,
+	}
+
+	w := l.WithField("source", "driver").Writer(log.WarnLevel)
+	fmt.Fprint(w, "first line\nsecond ")
+	fmt.Fprint(w, "line\r\n\n")
+	fmt.Fprint(w, "partial")
+
+	assert.Len(t, h.Entries, 2)
+	assert.Equal(t, "first line", h.Entries[0].Message)
+	assert.Equal(t, "second line", h.Entries[1].Message)
+	assert.Equal(t, log.WarnLevel, h.Entries[1].Level)
+	assert.Equal(t, log.Fields{"source": "driver"}, h.Entries[1].Fields)
+}
+
+func TestNewStdLogger(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:      h,
+		Level:        log.InfoLevel,
+		ReportCaller: true,
+	}
+
+	std := log.NewStdLogger(l, log.ErrorLevel)
+	_, _, line, _ := runtime.Caller(0)
+	std.Printf("http: TLS handshake error from %s", "127.0.0.1")
+
+	assert.Len(t, h.Entries, 1)
+	assert.Equal(t, "http: TLS handshake error from 127.0.0.1", h.Entries[0].Message)
+	assert.Equal(t, log.ErrorLevel, h.Entries[0].Level)
+	assert.Equal(t, fmt.Sprintf("stdlog_test.go:%d", line+1), h.Entries[0].Caller.String())
+}
+
+func TestStdPrintf_redirected(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: log.HandlerFunc(func(e *log.Entry) error {
+			log.StdPrintf("log/test: handled %s", e.Message)
+			return h.HandleLog(e)
+		}),
+		Level: log.InfoLevel,
+	}
+
+	stdlog.SetOutput(l.Writer(log.InfoLevel))
+	stdlog.SetFlags(0)
+	defer stdlog.SetOutput(os.Stderr)
+	defer stdlog.SetFlags(stdlog.LstdFlags)
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	stdlog.Print("hello")
+	l.Info("world")
+	w.Close()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	assert.Equal(t, "log/test: handled hello\nlog/test: handled world\n", buf.String())
+	assert.Equal(t, []string{"hello", "world"}, h.Messages())
+}